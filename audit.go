@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// ErrIncompleteAuditEntry is returned when an audit entry misses one of its mandatory fields
+var ErrIncompleteAuditEntry = errors.New("audit entry requires actor, action, resource and outcome")
+
+// AuditEntry is a security audit event, all of its fields are mandatory
+type AuditEntry struct {
+	Actor    string // who performed the action
+	Action   string // what was done
+	Resource string // what it was done to
+	Outcome  string // how it ended, e.g. success, failure, denied
+}
+
+// IAuditLogger is the interface for the audit logger
+type IAuditLogger interface {
+	Log(entry AuditEntry, fields ...interface{}) error
+}
+
+var (
+	auditLogger         atomic.Pointer[zapAuditLogger]
+	fallbackAuditLogger *zapAuditLogger
+	fallbackAuditOnce   sync.Once
+)
+
+// Audit returns the audit logger. Audit logs are kept apart from application logs in their own
+// file with their own retention and are never sampled or filtered by level. Until a logger with
+// audit logging enabled is initialized they are written to std out.
+func Audit() IAuditLogger {
+	if a := auditLogger.Load(); a != nil {
+		return a
+	}
+	fallbackAuditOnce.Do(func() {
+		fallbackAuditLogger = newAuditLogger(getZapLoggerConfig(nil).EncoderConfig, newCountingWriteSyncer(sinkAudit, zapcore.AddSync(os.Stdout)), nil)
+	})
+	return fallbackAuditLogger
+}
+
+type zapAuditLogger struct {
+	logger *zap.Logger
+}
+
+func newZapAuditLogger(config *LoggerConfig, loggerConfig zap.Config) *zapAuditLogger {
+	if config == nil || !config.AuditLoggingEnabled {
+		return nil
+	}
+	lumberjackLogger := &lumberjack.Logger{
+		Filename:   config.AuditFilePath,
+		MaxSize:    config.AuditFileMaxSize,
+		MaxBackups: config.AuditFileMaxBackups,
+		MaxAge:     config.AuditFileMaxAge,
+		Compress:   config.AuditFileCompress,
+		LocalTime:  true,
+	}
+	registerCloser(lumberjackLogger.Close)
+	return newAuditLogger(loggerConfig.EncoderConfig, newCountingWriteSyncer(sinkAudit, zapcore.AddSync(lumberjackLogger)), config)
+}
+
+func newAuditLogger(encoderConfig zapcore.EncoderConfig, writeSyncer zapcore.WriteSyncer, config *LoggerConfig, opts ...zap.Option) *zapAuditLogger {
+	// Audit logs are always JSON and every level is enabled
+	allLevels := zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+	core := newIOCore(zapcore.NewJSONEncoder(encoderConfig), writeSyncer, allLevels)
+
+	osHostname := getHostname(config)
+	var serviceName string
+	if config != nil {
+		serviceName = config.ServiceName
+	} else {
+		serviceName = os.Getenv("SERVICE")
+	}
+	fs := []zap.Field{zap.String("stream", "audit"), zap.Any(logformat.HostKey, osHostname), zap.Any(logformat.ServiceKey, serviceName)}
+	return &zapAuditLogger{logger: zap.New(core, append(opts, zap.Fields(fs...))...)}
+}
+
+// Log writes the audit entry along with the additional key value fields
+func (a *zapAuditLogger) Log(entry AuditEntry, fields ...interface{}) error {
+	if entry.Actor == "" || entry.Action == "" || entry.Resource == "" || entry.Outcome == "" {
+		return ErrIncompleteAuditEntry
+	}
+	preprocessLog(fields)
+	fields = append([]interface{}{
+		"actor", entry.Actor,
+		"action", entry.Action,
+		"resource", entry.Resource,
+		"outcome", entry.Outcome,
+	}, fields...)
+	a.logger.Sugar().Infow("audit", fields...)
+	return nil
+}
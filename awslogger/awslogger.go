@@ -0,0 +1,49 @@
+// Package awslogger provides a logger for the AWS SDK Go v2 writing through the generic logger,
+// so SDK retries and request logs are written with component=aws-sdk.
+package awslogger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/smithy-go/logging"
+	logger "github.com/piyushkumar96/generic-logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger adapts an ILogger to the logging.Logger and logging.ContextLogger interfaces of the SDK.
+// WARN classified messages are logged at WARN, DEBUG ones at DEBUG and any other at INFO.
+type Logger struct {
+	logger logger.ILogger
+	ctx    context.Context
+}
+
+// New creates an AWS SDK logger writing through the logger, nil uses the global logger. Assign it
+// to aws.Config.Logger along with the wanted aws.ClientLogMode.
+func New(l logger.ILogger) *Logger {
+	return &Logger{logger: l}
+}
+
+// Logf implements logging.Logger
+func (a *Logger) Logf(classification logging.Classification, format string, v ...interface{}) {
+	level := zapcore.InfoLevel
+	switch classification {
+	case logging.Warn:
+		level = zapcore.WarnLevel
+	case logging.Debug:
+		level = zapcore.DebugLevel
+	}
+
+	l := a.logger
+	if l == nil {
+		l = logger.WithContext(a.ctx)
+	}
+	logger.LogAt(l, level, strings.TrimSpace(fmt.Sprintf(format, v...)), logger.ComponentKey, "aws-sdk")
+}
+
+// WithContext implements logging.ContextLogger, the global logger is then obtained with the
+// context's tenant fields and debug marker
+func (a *Logger) WithContext(ctx context.Context) logging.Logger {
+	return &Logger{logger: a.logger, ctx: ctx}
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"errors"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// Reasons of the entries dropped before reaching the sinks
+const (
+	DropReasonSampled  = "sampled"  // dropped by sampling
+	DropReasonFiltered = "filtered" // dropped by a filter
+)
+
+// EntryInfo is the metadata of an entry reported to the OnDropped and OnWriteError callbacks, only
+// the time is set for failures not tied to an entry, such as dialing the socket
+type EntryInfo struct {
+	Level   zapcore.Level
+	Time    time.Time
+	Message string
+}
+
+// DropCallback is invoked with an entry that is not written and the reason, e.g. DropReasonSampled
+type DropCallback func(entry EntryInfo, reason string)
+
+// WriteErrorCallback is invoked with an entry whose write to the sink, e.g. file or socket, failed
+type WriteErrorCallback func(entry EntryInfo, sink string, err error)
+
+var (
+	// dropCallbacks and writeErrorCallbacks hold the registered callbacks
+	dropCallbacks       cowList[DropCallback]
+	writeErrorCallbacks cowList[WriteErrorCallback]
+)
+
+// OnDropped registers a callback invoked for every entry of an enabled level dropped by sampling or
+// by a filter before reaching the sinks, so applications can surface it to their own alerting.
+// Callbacks run on the logging goroutine, they must be fast and must not log. It can be removed
+// with the returned function.
+func OnDropped(callback DropCallback) (remove func()) {
+	return dropCallbacks.add(callback)
+}
+
+// OnWriteError registers a callback invoked for every failed write of an entry to a sink and every
+// failed dial of the socket, so applications can surface it to their own alerting. Callbacks run
+// on the logging goroutine, they must be fast and must not log. It can be removed with the
+// returned function.
+func OnWriteError(callback WriteErrorCallback) (remove func()) {
+	return writeErrorCallbacks.add(callback)
+}
+
+func entryInfo(entry zapcore.Entry) EntryInfo {
+	return EntryInfo{Level: entry.Level, Time: entry.Time, Message: entry.Message}
+}
+
+func reportDropped(entry zapcore.Entry, reason string) {
+	for _, callback := range dropCallbacks.load() {
+		callback(entryInfo(entry), reason)
+	}
+}
+
+func reportWriteError(entry zapcore.Entry, sink string, err error) {
+	for _, callback := range writeErrorCallbacks.load() {
+		callback(entryInfo(entry), sink, err)
+	}
+}
+
+// reportSinkError reports a failure of the sink not tied to an entry
+func reportSinkError(sink string, err error) {
+	reportWriteError(zapcore.Entry{Time: time.Now()}, sink, err)
+}
+
+// sinkError is a failed write to a sink, handled when the sink already recovered from it, e.g.
+// the socket falling back to std out
+type sinkError struct {
+	sink    string
+	err     error
+	handled bool
+}
+
+func (e *sinkError) Error() string {
+	return e.sink + " sink: " + e.err.Error()
+}
+
+func (e *sinkError) Unwrap() error {
+	return e.err
+}
+
+// reportingCore reports the failed writes of the wrapped core to the OnWriteError callbacks
+type reportingCore struct {
+	zapcore.Core
+}
+
+// newIOCore creates a core writing to the sinks of the write syncer, reporting failed writes
+func newIOCore(encoder zapcore.Encoder, writeSyncer zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
+	return &reportingCore{Core: zapcore.NewCore(encoder, writeSyncer, level)}
+}
+
+// Level returns the minimum enabled level of the wrapped core, including levels below DEBUG
+func (c *reportingCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.Core)
+}
+
+func (c *reportingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &reportingCore{Core: c.Core.With(fields)}
+}
+
+func (c *reportingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write returns the write errors the sinks did not handle themselves, zap reports them to its
+// error output
+func (c *reportingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	err := c.Core.Write(entry, fields)
+	if err == nil {
+		return nil
+	}
+	var unhandled error
+	for _, e := range multierr.Errors(err) {
+		var se *sinkError
+		if !errors.As(e, &se) {
+			reportWriteError(entry, "", e)
+			unhandled = multierr.Append(unhandled, e)
+			continue
+		}
+		reportWriteError(entry, se.sink, se.err)
+		if !se.handled {
+			unhandled = multierr.Append(unhandled, e)
+		}
+	}
+	return unhandled
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingWriteSyncer fails every write
+type failingWriteSyncer struct{ err error }
+
+func (f failingWriteSyncer) Write([]byte) (int, error) { return 0, f.err }
+func (f failingWriteSyncer) Sync() error               { return nil }
+
+// captureDropped records the entries reported to the OnDropped callbacks for the test duration
+func captureDropped(t *testing.T) func() map[string][]EntryInfo {
+	t.Helper()
+	var (
+		dropped = map[string][]EntryInfo{}
+		mu      sync.Mutex
+	)
+	t.Cleanup(OnDropped(func(entry EntryInfo, reason string) {
+		mu.Lock()
+		dropped[reason] = append(dropped[reason], entry)
+		mu.Unlock()
+	}))
+	return func() map[string][]EntryInfo {
+		mu.Lock()
+		defer mu.Unlock()
+		return dropped
+	}
+}
+
+func TestOnDroppedReportsFilteredEntries(t *testing.T) {
+	captureEntries(t, "health check")
+	dropped := captureDropped(t)
+	t.Cleanup(AddFilter(FieldEquals("path", "/healthz")))
+
+	log := Get("probes")
+	log.Info("health check", "path", "/healthz")
+	log.Info("health check", "path", "/orders")
+
+	filtered := dropped()[DropReasonFiltered]
+	if len(filtered) != 1 {
+		t.Fatalf("got %d filtered entries, want 1", len(filtered))
+	}
+	if filtered[0].Level != zapcore.InfoLevel || filtered[0].Message != "health check" || filtered[0].Time.IsZero() {
+		t.Errorf("got %+v, want the info entry of the health check", filtered[0])
+	}
+}
+
+func TestOnDroppedReportsSampledEntries(t *testing.T) {
+	withSampling(t, true, 1, 0)
+	dropped := captureDropped(t)
+	core := newSampledCore(newIOCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zap.NewAtomicLevelAt(TraceLevel)))
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		writeEntry(core, zapcore.InfoLevel, "retrying", now)
+	}
+
+	if sampled := dropped()[DropReasonSampled]; len(sampled) != 2 {
+		t.Errorf("got %d sampled entries, want 2", len(sampled))
+	}
+}
+
+func TestOnWriteErrorReportsFailedWrites(t *testing.T) {
+	var got []error
+	t.Cleanup(OnWriteError(func(entry EntryInfo, sink string, err error) {
+		if sink == sinkFile && entry.Message == "disk full" {
+			got = append(got, err)
+		}
+	}))
+	errWrite := errors.New("no space left on device")
+	core := newIOCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), newCountingWriteSyncer(sinkFile, failingWriteSyncer{errWrite}), zap.NewAtomicLevelAt(TraceLevel))
+
+	writeEntry(core, zapcore.ErrorLevel, "disk full", time.Now())
+
+	if len(got) != 1 || !errors.Is(got[0], errWrite) {
+		t.Errorf("got %v, want the write error", got)
+	}
+}
+
+func TestOnWriteErrorReportsSocketDialFailures(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+	t.Setenv("LOGGER_SOCKET_ADDRESS", "127.0.0.1")
+	t.Setenv("LOGGER_SOCKET_PORT", strconv.Itoa(port))
+
+	var got []error
+	t.Cleanup(OnWriteError(func(entry EntryInfo, sink string, err error) {
+		if sink == sinkSocket {
+			got = append(got, err)
+		}
+	}))
+
+	if syncer := NewSocketSyncer(NewDefaultLoggerConfig()); syncer != nil {
+		t.Fatal("got a socket syncer, want nil on a closed port")
+	}
+	if len(got) != 1 {
+		t.Errorf("got %d socket errors, want 1", len(got))
+	}
+}
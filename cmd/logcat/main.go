@@ -0,0 +1,305 @@
+// Command logcat pretty-prints the JSON logs written by the logger, read from files or std in,
+// with colored levels, level filtering and field queries:
+//
+//	logcat -level warn -where component=payments -f /var/log/app.log
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"github.com/piyushkumar96/generic-logger/internal/tty"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// The logger encodes entries with zap's production encoder config and adds the host and svc fields
+var encoderConfig = zap.NewProductionEncoderConfig()
+
+// followInterval is how often a followed file is polled for new entries
+const followInterval = 500 * time.Millisecond
+
+var levelColors = map[zapcore.Level]string{
+	logformat.TraceLevel: "\x1b[36m",
+	zapcore.DebugLevel:   "\x1b[35m",
+	zapcore.InfoLevel:    "\x1b[34m",
+	zapcore.WarnLevel:    "\x1b[33m",
+	zapcore.ErrorLevel:   "\x1b[31m",
+	zapcore.DPanicLevel:  "\x1b[31m",
+	zapcore.PanicLevel:   "\x1b[31m",
+	zapcore.FatalLevel:   "\x1b[31m",
+}
+
+const colorReset = "\x1b[0m"
+
+// conditions are the key=value field queries an entry must all match
+type conditions map[string]string
+
+func (c conditions) String() string {
+	pairs := make([]string, 0, len(c))
+	for key, value := range c {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (c conditions) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid query %q: must be key=value", s)
+	}
+	c[key] = value
+	return nil
+}
+
+type options struct {
+	minLevel zapcore.Level
+	where    conditions
+	color    bool
+}
+
+func main() {
+	where := conditions{}
+	level := flag.String("level", "trace", "minimum level of the entries to print, e.g. warn")
+	follow := flag.Bool("f", false, "keep reading the file as it grows, following rotations")
+	noColor := flag.Bool("no-color", false, "disable colors, they are disabled as well when std out is not a terminal")
+	flag.Var(where, "where", "print only the entries whose field equals the value, key=value, repeatable")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: logcat [flags] [file ...]\n\nReads std in when no file is given.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	opts := options{where: where, color: !*noColor && tty.IsTerminal(os.Stdout)}
+	var err error
+	if opts.minLevel, err = logformat.ParseLevel(*level); err != nil {
+		fmt.Fprintln(os.Stderr, "logcat: invalid level:", *level)
+		os.Exit(2)
+	}
+	if *follow && flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "logcat: -f requires exactly one file")
+		os.Exit(2)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	if flag.NArg() == 0 {
+		if err := cat(out, os.Stdin, opts); err != nil {
+			fmt.Fprintln(os.Stderr, "logcat:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *follow {
+		if err := tail(context.Background(), out, flag.Arg(0), opts); err != nil {
+			fmt.Fprintln(os.Stderr, "logcat:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	status := 0
+	for _, path := range flag.Args() {
+		if err := catFile(out, path, opts); err != nil {
+			fmt.Fprintln(os.Stderr, "logcat:", err)
+			status = 1
+		}
+	}
+	out.Flush()
+	os.Exit(status)
+}
+
+func catFile(out *bufio.Writer, path string, opts options) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return cat(out, f, opts)
+}
+
+// cat prints the entries of r until EOF
+func cat(out *bufio.Writer, r io.Reader, opts options) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			printLine(out, line, opts)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// tail prints the entries of the file as it grows until the context is done, reopening it when it
+// is rotated
+func tail(ctx context.Context, out *bufio.Writer, path string, opts options) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	// next is the file at the path once the open one was rotated, opened before switching so the
+	// entries written to the rotated file meanwhile are read first
+	var next *os.File
+	defer func() {
+		f.Close()
+		if next != nil {
+			next.Close()
+		}
+	}()
+
+	reader := bufio.NewReader(f)
+	var partial []byte
+	for {
+		line, err := reader.ReadBytes('\n')
+		partial = append(partial, line...)
+		if err == nil {
+			printLine(out, partial, opts)
+			partial = partial[:0]
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+
+		if next != nil {
+			// The rotated file is read to its end, continue with the new one
+			if len(partial) > 0 {
+				printLine(out, partial, opts)
+				partial = partial[:0]
+			}
+			f.Close()
+			f, next = next, nil
+			reader.Reset(f)
+			continue
+		}
+		out.Flush()
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(followInterval):
+		}
+		if rotated(f, path) {
+			if opened, err := os.Open(path); err == nil {
+				next = opened
+			}
+		}
+	}
+}
+
+// rotated reports whether the path no longer names the open file
+func rotated(f *os.File, path string) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	opened, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return !os.SameFile(current, opened)
+}
+
+// printLine prints the entry of the line if it passes the filters. Lines which are not JSON, e.g.
+// console encoded ones, are printed as they are unless filters are set.
+func printLine(out *bufio.Writer, line []byte, opts options) {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return
+	}
+
+	entry := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&entry); err != nil {
+		if opts.minLevel <= logformat.TraceLevel && len(opts.where) == 0 {
+			out.Write(line)
+			out.WriteByte('\n')
+		}
+		return
+	}
+
+	var level zapcore.Level
+	if s, ok := entry[encoderConfig.LevelKey].(string); ok {
+		level, _ = logformat.ParseLevel(s)
+	}
+	if level < opts.minLevel {
+		return
+	}
+	for key, value := range opts.where {
+		v, ok := entry[key]
+		if !ok || stringify(v) != value {
+			return
+		}
+	}
+
+	if ts := stringify(entry[encoderConfig.TimeKey]); ts != "" {
+		out.WriteString(ts + " ")
+	}
+	levelText := fmt.Sprintf("%-5s", strings.ToUpper(logformat.LevelName(level)))
+	if opts.color {
+		levelText = levelColors[level] + levelText + colorReset
+	}
+	out.WriteString(levelText)
+	for _, key := range []string{logformat.ServiceKey, logformat.HostKey, encoderConfig.CallerKey} {
+		if v, ok := entry[key]; ok && stringify(v) != "" {
+			out.WriteString(" [" + stringify(v) + "]")
+		}
+	}
+	out.WriteString(" " + stringify(entry[encoderConfig.MessageKey]))
+
+	known := map[string]bool{
+		encoderConfig.TimeKey: true, encoderConfig.LevelKey: true, encoderConfig.MessageKey: true,
+		encoderConfig.CallerKey: true, encoderConfig.StacktraceKey: true, logformat.ServiceKey: true, logformat.HostKey: true,
+	}
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		if !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		out.WriteString(" " + key + "=" + stringify(entry[key]))
+	}
+	out.WriteByte('\n')
+	if stack, ok := entry[encoderConfig.StacktraceKey].(string); ok && stack != "" {
+		out.WriteString(stack)
+		out.WriteByte('\n')
+	}
+}
+
+// stringify renders a decoded JSON value, objects and arrays as compact JSON
+func stringify(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
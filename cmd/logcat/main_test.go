@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"go.uber.org/zap/zapcore"
+)
+
+func printed(line string, opts options) string {
+	buf := &bytes.Buffer{}
+	out := bufio.NewWriter(buf)
+	printLine(out, []byte(line), opts)
+	out.Flush()
+	return buf.String()
+}
+
+func TestPrintLine(t *testing.T) {
+	entry := `{"level":"warn","ts":"2024-01-01T00:00:00Z","caller":"app/main.go:12","msg":"slow query","svc":"api","host":"h1","duration_ms":250,"tags":["a"]}` + "\n"
+	trace := `{"level":"trace","ts":"2024-01-01T00:00:01Z","msg":"row scanned","row":7}`
+	all := options{minLevel: logformat.TraceLevel, where: conditions{}}
+
+	tests := []struct {
+		name string
+		line string
+		opts options
+		want string
+	}{
+		{"entry", entry, all, "2024-01-01T00:00:00Z WARN  [api] [h1] [app/main.go:12] slow query duration_ms=250 tags=[\"a\"]\n"},
+		{"at the minimum level", entry, options{minLevel: zapcore.WarnLevel}, "2024-01-01T00:00:00Z WARN  [api] [h1] [app/main.go:12] slow query duration_ms=250 tags=[\"a\"]\n"},
+		{"below the minimum level", entry, options{minLevel: zapcore.ErrorLevel}, ""},
+		{"matching where", entry, options{minLevel: logformat.TraceLevel, where: conditions{"svc": "api", "duration_ms": "250"}}, "2024-01-01T00:00:00Z WARN  [api] [h1] [app/main.go:12] slow query duration_ms=250 tags=[\"a\"]\n"},
+		{"other where value", entry, options{minLevel: logformat.TraceLevel, where: conditions{"svc": "web"}}, ""},
+		{"missing where field", entry, options{minLevel: logformat.TraceLevel, where: conditions{"component": "db"}}, ""},
+		{"trace", trace, all, "2024-01-01T00:00:01Z TRACE row scanned row=7\n"},
+		{"trace below debug", trace, options{minLevel: zapcore.DebugLevel}, ""},
+		{"colored", trace, options{minLevel: logformat.TraceLevel, color: true}, "2024-01-01T00:00:01Z \x1b[36mTRACE\x1b[0m row scanned row=7\n"},
+		{"not JSON", "2024-01-01\tINFO\tstarted\r\n", all, "2024-01-01\tINFO\tstarted\n"},
+		{"not JSON with a level filter", "2024-01-01\tINFO\tstarted", options{minLevel: zapcore.InfoLevel}, ""},
+		{"not JSON with a where filter", "2024-01-01\tINFO\tstarted", options{minLevel: logformat.TraceLevel, where: conditions{"svc": "api"}}, ""},
+		{"empty", "\n", all, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := printed(tt.line, tt.opts); got != tt.want {
+				t.Errorf("printLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionsSet(t *testing.T) {
+	where := conditions{}
+	if err := where.Set("svc=api=v2"); err != nil || where["svc"] != "api=v2" {
+		t.Errorf("Set(svc=api=v2) = %v with %v, want svc set to api=v2", err, where)
+	}
+	for _, invalid := range []string{"svc", "=api"} {
+		if err := where.Set(invalid); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", invalid)
+		}
+	}
+}
+
+// syncBuffer is a buffer written by the tail goroutine and read by the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func waitFor(t *testing.T, buf *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("output %q does not contain %q", buf.String(), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTailFollowsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	appendFile(t, path, `{"level":"info","msg":"first"}`+"\n")
+
+	buf := &syncBuffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- tail(ctx, bufio.NewWriter(buf), path, options{minLevel: logformat.TraceLevel})
+	}()
+	waitFor(t, buf, "first")
+
+	// Written to the file just before it is rotated, still read before switching to the new one
+	appendFile(t, path, `{"level":"info","msg":"before rotation"}`+"\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path, `{"level":"info","msg":"after rotation"}`+"\n")
+	waitFor(t, buf, "after rotation")
+
+	// A partial line is printed once complete
+	appendFile(t, path, `{"level":"info",`)
+	time.Sleep(2 * followInterval)
+	appendFile(t, path, `"msg":"completed"}`+"\n")
+	waitFor(t, buf, "completed")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "INFO  first\nINFO  before rotation\nINFO  after rotation\nINFO  completed\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
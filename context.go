@@ -0,0 +1,28 @@
+package logger
+
+import (
+	"context"
+)
+
+// WithContext returns the logger with the tenant fields of the context attached, logging at DEBUG
+// if the context is marked by ContextWithDebug. With tenant routing enabled the entries of a
+// tenant are written to the tenant's own file only.
+func WithContext(ctx context.Context) ILogger {
+	z := currentLogger()
+	if z == nil {
+		return nopLogger
+	}
+
+	fields, tenantID := resolveTenantFields(ctx)
+	debug := IsDebugContext(ctx)
+	sugar := z.sugar
+	if l, ok := z.build.tenantLogger(tenantID, debug); ok {
+		sugar = l.Sugar()
+	} else if debug {
+		sugar = z.build.debugZapLogger().Sugar()
+	}
+	if len(fields) == 0 && sugar == z.sugar {
+		return z
+	}
+	return &zapLogger{sugar: sugar, fields: append(append([]interface{}(nil), z.fields...), fields...), build: z.build}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// cowList is a list read on every entry, such as the hooks or filters. It is replaced as a whole
+// on every change so logging never locks, changes are serialized by the mutex.
+type cowList[T any] struct {
+	mu      sync.Mutex
+	current atomic.Pointer[cowItems[T]]
+	nextID  uint64
+}
+
+// cowItems are the items of a cowList with the ids to remove them by
+type cowItems[T any] struct {
+	ids   []uint64
+	items []T
+}
+
+// add appends the item and returns a function removing it
+func (l *cowList[T]) add(item T) (remove func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nextID++
+	id := l.nextID
+	next := &cowItems[T]{}
+	if current := l.current.Load(); current != nil {
+		next.ids = append(next.ids, current.ids...)
+		next.items = append(next.items, current.items...)
+	}
+	next.ids = append(next.ids, id)
+	next.items = append(next.items, item)
+	l.current.Store(next)
+	return func() { l.remove(id) }
+}
+
+func (l *cowList[T]) remove(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current := l.current.Load()
+	if current == nil {
+		return
+	}
+	next := &cowItems[T]{ids: make([]uint64, 0, len(current.ids)), items: make([]T, 0, len(current.items))}
+	for i, itemID := range current.ids {
+		if itemID != id {
+			next.ids = append(next.ids, itemID)
+			next.items = append(next.items, current.items[i])
+		}
+	}
+	l.current.Store(next)
+}
+
+// clear removes all items
+func (l *cowList[T]) clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current.Store(nil)
+}
+
+// load returns the items in order of addition, the slice must not be modified
+func (l *cowList[T]) load() []T {
+	if current := l.current.Load(); current != nil {
+		return current.items
+	}
+	return nil
+}
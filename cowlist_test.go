@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCowListRemovesByID(t *testing.T) {
+	var list cowList[string]
+	removeA := list.add("a")
+	list.add("b")
+	removeC := list.add("c")
+	before := list.load()
+
+	removeA()
+	removeA()
+	if got := list.load(); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("after removing a got %v, want [b c]", got)
+	}
+	if !reflect.DeepEqual(before, []string{"a", "b", "c"}) {
+		t.Errorf("a loaded list changed to %v", before)
+	}
+
+	list.clear()
+	removeC()
+	if got := list.load(); len(got) != 0 {
+		t.Errorf("after clear got %v, want none", got)
+	}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"time"
+)
+
+// CronLogger adapts an ILogger to robfig/cron's cron.Logger interface. cron's scheduling chatter
+// is logged at DEBUG like its default logger hides it, errors such as recovered panics at ERROR.
+type CronLogger struct {
+	logger ILogger
+}
+
+// NewCronLogger creates a cron logger writing through the logger, nil uses the global logger.
+// Pass it with cron.WithLogger.
+func NewCronLogger(logger ILogger) *CronLogger {
+	return &CronLogger{logger: logger}
+}
+
+func (c *CronLogger) current() ILogger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return L()
+}
+
+// Info implements cron.Logger
+func (c *CronLogger) Info(msg string, keysAndValues ...interface{}) {
+	c.current().Debug(msg, append([]interface{}{ComponentKey, "cron"}, keysAndValues...)...)
+}
+
+// Error implements cron.Logger
+func (c *CronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	fields := append([]interface{}{ComponentKey, "cron"}, keysAndValues...)
+	if err != nil {
+		fields = append(fields, "err", err.Error())
+	}
+	c.current().Error(msg, fields...)
+}
+
+// CronJob wraps a scheduled job so its start, finish and failure are logged with the job name and
+// schedule, the returned function can be passed to cron's AddFunc
+func CronJob(name, schedule string, job func() error) func() {
+	return func() {
+		logger := L()
+		start := time.Now()
+		logger.Info("cron job started", ComponentKey, "cron", "job", name, "schedule", schedule)
+		if err := job(); err != nil {
+			logger.Error("cron job failed", ComponentKey, "cron", "job", name, "schedule", schedule,
+				"duration_ms", float64(time.Since(start))/float64(time.Millisecond), "err", err.Error())
+			return
+		}
+		logger.Info("cron job finished", ComponentKey, "cron", "job", name, "schedule", schedule,
+			"duration_ms", float64(time.Since(start))/float64(time.Millisecond))
+	}
+}
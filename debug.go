@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type debugContextKey struct{}
+
+// ContextWithDebug marks the context so that loggers obtained with WithContext log at DEBUG for
+// its whole call chain, regardless of the configured log mode
+func ContextWithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugContextKey{}, true)
+}
+
+// IsDebugContext reports whether the context is marked for DEBUG logging
+func IsDebugContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	debug, _ := ctx.Value(debugContextKey{}).(bool)
+	return debug
+}
+
+// DebugSelector reports whether a request should be logged at DEBUG, e.g. based on a feature flag
+type DebugSelector func(r *http.Request) bool
+
+// DebugHeader selects the requests carrying the header with a true value, e.g. X-Debug-Log: true
+func DebugHeader(header string) DebugSelector {
+	return func(r *http.Request) bool {
+		debug, _ := strconv.ParseBool(r.Header.Get(header))
+		return debug
+	}
+}
+
+// DebugRequestIDs selects the requests whose request id, read from the header, is in the allowlist
+func DebugRequestIDs(header string, requestIDs ...string) DebugSelector {
+	allowlist := make(map[string]bool, len(requestIDs))
+	for _, id := range requestIDs {
+		allowlist[id] = true
+	}
+	return func(r *http.Request) bool {
+		return allowlist[r.Header.Get(header)]
+	}
+}
+
+// DebugMiddleware marks the context of the requests matched by any of the selectors for DEBUG logging
+func DebugMiddleware(next http.Handler, selectors ...DebugSelector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, selector := range selectors {
+			if selector(r) {
+				r = r.WithContext(ContextWithDebug(r.Context()))
+				break
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// debugEnabler enables DEBUG, or the configured level when it is lower, e.g. TRACE
+type debugEnabler struct {
+	level zap.AtomicLevel
+}
+
+func (d debugEnabler) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.DebugLevel || d.level.Enabled(level)
+}
+
+// Level returns the minimum enabled level, so levels below DEBUG are reported as well
+func (d debugEnabler) Level() zapcore.Level {
+	if level := d.level.Level(); level < zapcore.DebugLevel {
+		return level
+	}
+	return zapcore.DebugLevel
+}
+
+// debugZapLogger returns the logger writing to the shared sinks with DEBUG enabled
+func (b *zapBuild) debugZapLogger() *zap.Logger {
+	b.debugOnce.Do(func() {
+		b.debugLogger = zap.New(b.sampledCore(b.writeSyncer, debugEnabler{level: b.level}), b.options...)
+	})
+	return b.debugLogger
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnknownEvent is returned when logging an event whose type was never registered
+	ErrUnknownEvent = errors.New("unknown event")
+	// ErrIncompleteEvent is returned when logging an event that misses required fields
+	ErrIncompleteEvent = errors.New("incomplete event")
+)
+
+// EventFields are the fields of a typed event
+type EventFields map[string]interface{}
+
+var (
+	eventSchemas   = map[string][]string{}
+	eventSchemasMu sync.RWMutex
+)
+
+// RegisterEvent registers a named event type with the fields every event of that type must carry,
+// registering the same name again replaces its required fields
+func RegisterEvent(name string, requiredFields ...string) {
+	eventSchemasMu.Lock()
+	defer eventSchemasMu.Unlock()
+	eventSchemas[name] = append([]string(nil), requiredFields...)
+}
+
+// Event logs a typed event at INFO. Unknown or incomplete events are still logged, at WARN with an
+// "event_error" field, and the reason is returned.
+func Event(name string, fields EventFields) error {
+	err := validateEvent(name, fields)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	logFields := make([]interface{}, 0, 2*len(fields)+4)
+	logFields = append(logFields, "event", name)
+	for _, key := range keys {
+		logFields = append(logFields, key, fields[key])
+	}
+
+	// The proxied logger skips one frame more than the global one, so the caller of Event is reported
+	logger := globalLogger{}.current()
+	if err != nil {
+		logFields = append(logFields, "event_error", err.Error())
+		logger.Warn(name, logFields...)
+		return err
+	}
+	logger.Info(name, logFields...)
+	return nil
+}
+
+func validateEvent(name string, fields EventFields) error {
+	eventSchemasMu.RLock()
+	requiredFields, ok := eventSchemas[name]
+	eventSchemasMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownEvent, name)
+	}
+
+	var missingFields []string
+	for _, field := range requiredFields {
+		if _, ok := fields[field]; !ok {
+			missingFields = append(missingFields, field)
+		}
+	}
+	if len(missingFields) > 0 {
+		return fmt.Errorf("%w: %q is missing %s", ErrIncompleteEvent, name, strings.Join(missingFields, ", "))
+	}
+	return nil
+}
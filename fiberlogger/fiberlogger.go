@@ -0,0 +1,70 @@
+// Package fiberlogger provides fiber middlewares writing access logs and recovered panics through
+// the generic logger, fiber's fasthttp context does not work with the net/http middleware.
+package fiberlogger
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	logger "github.com/piyushkumar96/generic-logger"
+)
+
+// Middleware logs method, path, route, status, latency, bytes, remote ip and request id of every
+// request, a nil config uses the default one. Errors returned by the handlers are passed to the
+// app's error handler first so the logged status is the one sent.
+func Middleware(config *logger.HTTPMiddlewareConfig) fiber.Handler {
+	if config == nil {
+		config = logger.NewDefaultHTTPMiddlewareConfig()
+	}
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if config.Skipped(c.Path(), status) {
+			return nil
+		}
+		fields := []interface{}{
+			"method", c.Method(),
+			"path", c.Path(),
+			"route", c.Route().Path,
+			"status", status,
+			"latency_ms", float64(time.Since(start)) / float64(time.Millisecond),
+			"bytes", len(c.Response().Body()),
+			"remote_ip", c.IP(),
+			"request_id", c.Get(config.RequestIDHeaderName()),
+		}
+		if err != nil {
+			fields = append(fields, "err", err.Error())
+		}
+		config.Log(logger.WithContext(c.UserContext()), status, fields...)
+		return nil
+	}
+}
+
+// Recovery recovers from panics in the handlers, logs them with their stack trace at ERROR and
+// returns a 500 error
+func Recovery() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.WithContext(c.UserContext()).Error("panic recovered",
+					"method", c.Method(),
+					"path", c.Path(),
+					"route", c.Route().Path,
+					"panic", fmt.Sprint(r),
+					"stack", string(debug.Stack()),
+				)
+				err = fiber.ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// ComponentKey is the field naming the component an entry originates from, set by Get and the adapters
+const ComponentKey = "component"
+
+// Filter reports whether an entry should be dropped
+type Filter func(Entry) bool
+
+// filters holds the registered filters
+var filters cowList[Filter]
+
+// AddFilter registers a filter dropping every entry it matches, it can be added and removed at
+// runtime with the returned function
+func AddFilter(filter Filter) (remove func()) {
+	return filters.add(filter)
+}
+
+// ClearFilters removes all registered filters
+func ClearFilters() {
+	filters.clear()
+}
+
+// MessageMatches returns a filter dropping entries whose message matches the pattern
+func MessageMatches(pattern *regexp.Regexp) Filter {
+	return func(entry Entry) bool {
+		return pattern.MatchString(entry.Message)
+	}
+}
+
+// FieldEquals returns a filter dropping entries having the field set to the value, values such as
+// slices and maps are compared deeply
+func FieldEquals(key string, value interface{}) Filter {
+	return func(entry Entry) bool {
+		for i := 1; i < len(entry.Fields); i += 2 {
+			if field, ok := entry.Fields[i-1].(string); ok && field == key && reflect.DeepEqual(entry.Fields[i], value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ComponentEquals returns a filter dropping entries logged by the component
+func ComponentEquals(component string) Filter {
+	return FieldEquals(ComponentKey, component)
+}
+
+func hasFilters() bool {
+	return len(filters.load()) > 0
+}
+
+// isFiltered reports whether any registered filter drops the entry
+func isFiltered(entry Entry) bool {
+	for _, filter := range filters.load() {
+		if filter(entry) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFieldEqualsComparesDeeply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		fields []interface{}
+		want   bool
+	}{
+		{"equal string", FieldEquals("path", "/healthz"), []interface{}{"path", "/healthz"}, true},
+		{"other string", FieldEquals("path", "/healthz"), []interface{}{"path", "/orders"}, false},
+		{"other key", FieldEquals("path", "/healthz"), []interface{}{"route", "/healthz"}, false},
+		// Slices and maps are not comparable with ==, which panicked on the logging goroutine
+		{"equal slice", FieldEquals("tags", []string{"a", "b"}), []interface{}{"tags", []string{"a", "b"}}, true},
+		{"other slice", FieldEquals("tags", []string{"a", "b"}), []interface{}{"tags", []string{"a"}}, false},
+		{"equal map", FieldEquals("meta", map[string]interface{}{"id": 1}), []interface{}{"meta", map[string]interface{}{"id": 1}}, true},
+		{"map against string", FieldEquals("meta", "id"), []interface{}{"meta", map[string]interface{}{"id": 1}}, false},
+		{"component", ComponentEquals("payments"), []interface{}{ComponentKey, "payments"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter(Entry{Message: "m", Fields: tt.fields}); got != tt.want {
+				t.Errorf("filter(%v) = %v, want %v", tt.fields, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddFilterRemovesAtRuntime(t *testing.T) {
+	remove := AddFilter(MessageMatches(regexp.MustCompile(`^noisy`)))
+	t.Cleanup(ClearFilters)
+	if !isFiltered(Entry{Message: "noisy entry"}) || isFiltered(Entry{Message: "useful entry"}) {
+		t.Error("the filter does not drop exactly the matching entries")
+	}
+	remove()
+	if isFiltered(Entry{Message: "noisy entry"}) {
+		t.Error("a removed filter still drops entries")
+	}
+}
@@ -0,0 +1,64 @@
+// Package ginlogger provides gin middlewares writing access logs and recovered panics through the
+// generic logger, replacing gin's default writer based logger and recovery.
+package ginlogger
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	logger "github.com/piyushkumar96/generic-logger"
+)
+
+// Middleware logs method, path, route, status, latency, bytes, remote ip and request id of every
+// request, a nil config uses the default one
+func Middleware(config *logger.HTTPMiddlewareConfig) gin.HandlerFunc {
+	if config == nil {
+		config = logger.NewDefaultHTTPMiddlewareConfig()
+	}
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		if config.Skipped(c.Request.URL.Path, status) {
+			return
+		}
+		fields := []interface{}{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", float64(time.Since(start)) / float64(time.Millisecond),
+			"bytes", max(c.Writer.Size(), 0),
+			"remote_ip", c.ClientIP(),
+			"request_id", c.GetHeader(config.RequestIDHeaderName()),
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, "err", c.Errors.String())
+		}
+		config.Log(logger.WithContext(c.Request.Context()), status, fields...)
+	}
+}
+
+// Recovery recovers from panics in the handlers, logs them with their stack trace at ERROR and
+// responds with 500
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.WithContext(c.Request.Context()).Error("panic recovered",
+					"method", c.Request.Method,
+					"path", c.Request.URL.Path,
+					"route", c.FullPath(),
+					"panic", fmt.Sprint(r),
+					"stack", string(debug.Stack()),
+				)
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// globalState holds the global logger, swapped as a whole so goroutines logging concurrently with
+// a re-initialization always see a complete logger
+type globalState struct {
+	logger  *zapLogger // used through L()
+	proxied *zapLogger // used through the Logger variable, skipping the frame of its forwarding method
+}
+
+var (
+	global atomic.Pointer[globalState]
+
+	// nopLogger is used until the logger is initialized
+	nopLogger = &zapLogger{sugar: zap.NewNop().Sugar()}
+)
+
+// L returns the global logger, a no-op logger until it is initialized
+func L() ILogger {
+	if z := currentLogger(); z != nil {
+		return z
+	}
+	return nopLogger
+}
+
+// currentLogger returns the global logger, nil until it is initialized
+func currentLogger() *zapLogger {
+	if state := global.Load(); state != nil {
+		return state.logger
+	}
+	return nil
+}
+
+// swapLogger atomically replaces the global logger, nil uninitializes it
+func swapLogger(z *zapLogger) {
+	if z == nil {
+		global.Store(nil)
+		return
+	}
+	proxied := &zapLogger{sugar: z.sugar.WithOptions(zap.AddCallerSkip(1)), fields: z.fields, build: z.build}
+	global.Store(&globalState{logger: z, proxied: proxied})
+}
+
+// globalLogger is the type of the Logger variable, forwarding every call to the global logger
+type globalLogger struct{}
+
+func (globalLogger) current() *zapLogger {
+	if state := global.Load(); state != nil {
+		return state.proxied
+	}
+	return nopLogger
+}
+
+func (g globalLogger) Write(p []byte) (int, error) {
+	return g.current().Write(p)
+}
+
+func (g globalLogger) Debug(message string, fields ...interface{}) {
+	g.current().Debug(message, fields...)
+}
+
+func (g globalLogger) Infof(message string, fields ...interface{}) {
+	g.current().Infof(message, fields...)
+}
+
+func (g globalLogger) Info(message string, fields ...interface{}) {
+	g.current().Info(message, fields...)
+}
+
+func (g globalLogger) Warn(message string, fields ...interface{}) {
+	g.current().Warn(message, fields...)
+}
+
+func (g globalLogger) Error(message string, fields ...interface{}) {
+	g.current().Error(message, fields...)
+}
+
+func (g globalLogger) Fatal(message string, fields ...interface{}) {
+	g.current().Fatal(message, fields...)
+}
+
+func (g globalLogger) log(level zapcore.Level, message string, fields []interface{}) {
+	g.current().log(level, message, fields)
+}
+
+func (g globalLogger) enabled(level zapcore.Level) bool {
+	return g.current().enabled(level)
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// GoKitLogger adapts an ILogger to go-kit's log.Logger interface. The "level" keyval, as set by
+// go-kit's level package, selects the level, INFO if missing, and the "msg" keyval becomes the message.
+type GoKitLogger struct {
+	logger ILogger
+}
+
+// NewGoKitLogger creates a go-kit logger writing through the logger, nil uses the global logger
+func NewGoKitLogger(logger ILogger) *GoKitLogger {
+	return &GoKitLogger{logger: logger}
+}
+
+// Log implements go-kit's log.Logger
+func (g *GoKitLogger) Log(keyvals ...interface{}) error {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, "(MISSING)")
+	}
+
+	level := zapcore.InfoLevel
+	var message string
+	fields := make([]interface{}, 0, len(keyvals))
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		switch key {
+		case "level":
+			level = parseKitLevel(fmt.Sprint(keyvals[i+1]))
+		case "msg":
+			message = fmt.Sprint(keyvals[i+1])
+		default:
+			fields = append(fields, key, keyvals[i+1])
+		}
+	}
+
+	logger := g.logger
+	if logger == nil {
+		logger = L()
+	}
+	LogAt(logger, level, message, fields...)
+	return nil
+}
+
+func parseKitLevel(level string) zapcore.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return zapcore.DebugLevel
+	case "warn", "warning":
+		return zapcore.WarnLevel
+	case "error":
+		return zapcore.ErrorLevel
+	}
+	return zapcore.InfoLevel
+}
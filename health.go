@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses of a sink
+const (
+	SinkStatusOK           = "ok"
+	SinkStatusFailing      = "failing"
+	SinkStatusConnected    = "connected"
+	SinkStatusDisconnected = "disconnected"
+)
+
+type sinkFailure struct {
+	err string
+	at  time.Time
+}
+
+// sinkState tracks whether the last write to a sink failed and the last error
+type sinkState struct {
+	failing     atomic.Bool
+	lastFailure atomic.Pointer[sinkFailure]
+}
+
+func (s *sinkState) failed(err error) {
+	s.lastFailure.Store(&sinkFailure{err: err.Error(), at: time.Now()})
+	s.failing.Store(true)
+}
+
+func (s *sinkState) ok() {
+	// Only written on recovery, successful writes do not contend on the flag
+	if s.failing.Load() {
+		s.failing.Store(false)
+	}
+}
+
+var (
+	sinkStates = map[string]*sinkState{
+		sinkConsole: new(sinkState),
+		sinkFile:    new(sinkState),
+		sinkSocket:  new(sinkState),
+		sinkAudit:   new(sinkState),
+	}
+	// configuredSinks are the sinks the initialized logger writes to
+	configuredSinks atomic.Pointer[[]string]
+)
+
+func setConfiguredSinks(sinks ...string) {
+	configuredSinks.Store(&sinks)
+}
+
+// SinkHealth is the status of a sink the logger writes to
+type SinkHealth struct {
+	Name         string     `json:"name"`
+	Status       string     `json:"status"` // ok or failing, connected or disconnected for the socket
+	BytesWritten uint64     `json:"bytes_written"`
+	WriteErrors  uint64     `json:"write_errors"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+}
+
+// SinksHealth returns the status of every sink the logger writes to, a sink is failing when its
+// last write failed. There is no queue depth to report, entries are written synchronously.
+func SinksHealth() []SinkHealth {
+	current := configuredSinks.Load()
+	if current == nil {
+		return nil
+	}
+	health := make([]SinkHealth, 0, len(*current))
+	for _, sink := range *current {
+		state := sinkStates[sink]
+		h := SinkHealth{
+			Name:         sink,
+			Status:       SinkStatusOK,
+			BytesWritten: sinkBytesCounts[sink].Load(),
+			WriteErrors:  sinkErrorCounts[sink].Load(),
+		}
+		failing := state.failing.Load()
+		if failing {
+			h.Status = SinkStatusFailing
+		}
+		if sink == sinkSocket {
+			h.Status = SinkStatusConnected
+			if failing {
+				h.Status = SinkStatusDisconnected
+			}
+		}
+		if failure := state.lastFailure.Load(); failure != nil {
+			at := failure.at
+			h.LastError, h.LastErrorAt = failure.err, &at
+		}
+		health = append(health, h)
+	}
+	return health
+}
+
+// SinkHealthHandler returns a handler reporting the status of the sinks as JSON, for readiness
+// probes and dashboards. It responds 503 when a sink is failing or the logger is not initialized.
+func SinkHealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sinks := SinksHealth()
+		healthy := sinks != nil
+		for _, sink := range sinks {
+			if sink.Status == SinkStatusFailing || sink.Status == SinkStatusDisconnected {
+				healthy = false
+			}
+		}
+		if sinks == nil {
+			sinks = []SinkHealth{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(struct {
+			Healthy bool         `json:"healthy"`
+			Sinks   []SinkHealth `json:"sinks"`
+		}{Healthy: healthy, Sinks: sinks})
+	})
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry is a log entry as it passes through the hook pipeline, before it is encoded
+type Entry struct {
+	Level   zapcore.Level
+	Message string
+	Fields  []interface{} // alternating keys and values
+}
+
+// Hook is invoked with every entry before it is encoded and returns the entry to be logged
+type Hook func(Entry) Entry
+
+// hooks holds the registered hooks
+var hooks cowList[Hook]
+
+// AddHook registers a hook invoked, in order of registration, for every entry of an enabled level
+// before it is encoded, so applications can enrich, count or mirror entries. It can be removed
+// with the returned function.
+func AddHook(hook Hook) (remove func()) {
+	return hooks.add(hook)
+}
+
+func hasHooks() bool {
+	return len(hooks.load()) > 0
+}
+
+func runHooks(entry Entry) Entry {
+	for _, hook := range hooks.load() {
+		entry = hook(entry)
+	}
+	return entry
+}
@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// HTTPMiddlewareConfig is the config for the access logging middlewares
+type HTTPMiddlewareConfig struct {
+	LogMode           string   // INFO, DEBUG, WARN, ERROR, level of the access logs, server errors are always logged at ERROR (default: INFO)
+	SkipPaths         []string // to skip logging requests to these paths, e.g. health checks (default: nil)
+	SuccessSampleRate float64  // to set the fraction of successful requests that are logged, 1 or 0 (unset) logs all (default: 1)
+	RequestIDHeader   string   // to set the header carrying the request id, empty uses "X-Request-Id" (default: "X-Request-Id")
+}
+
+// defaultRequestIDHeader is the header carrying the request id unless configured otherwise
+const defaultRequestIDHeader = "X-Request-Id"
+
+// NewDefaultHTTPMiddlewareConfig creates a new default access logging middleware config
+func NewDefaultHTTPMiddlewareConfig() *HTTPMiddlewareConfig {
+	return &HTTPMiddlewareConfig{
+		LogMode:           "INFO",
+		SkipPaths:         nil,
+		SuccessSampleRate: 1,
+		RequestIDHeader:   defaultRequestIDHeader,
+	}
+}
+
+// Skipped reports whether the access log of a request to the path that ended with the status is skipped
+func (c *HTTPMiddlewareConfig) Skipped(path string, status int) bool {
+	for _, skipPath := range c.SkipPaths {
+		if path == skipPath {
+			return true
+		}
+	}
+	// A zero rate is an unset field of a struct literal, not a request to drop every success
+	if status < http.StatusBadRequest && c.SuccessSampleRate > 0 && c.SuccessSampleRate < 1 {
+		return rand.Float64() >= c.SuccessSampleRate
+	}
+	return false
+}
+
+// RequestIDHeaderName returns the header carrying the request id, X-Request-Id when unset as in a
+// config built as a struct literal
+func (c *HTTPMiddlewareConfig) RequestIDHeaderName() string {
+	if c.RequestIDHeader == "" {
+		return defaultRequestIDHeader
+	}
+	return c.RequestIDHeader
+}
+
+// Log writes the access log of a request that ended with the status
+func (c *HTTPMiddlewareConfig) Log(l ILogger, status int, fields ...interface{}) {
+	level := parseLogMode(c.LogMode)
+	if status >= http.StatusInternalServerError {
+		level = zapcore.ErrorLevel
+	}
+	LogAt(l, level, "http request", fields...)
+}
+
+// HTTPMiddleware logs method, path, status, latency, bytes, remote ip and request id of every
+// request with the default config
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return HTTPMiddlewareWithConfig(next, NewDefaultHTTPMiddlewareConfig())
+}
+
+// HTTPMiddlewareWithConfig logs method, path, status, latency, bytes, remote ip and request id of
+// every request with the config
+func HTTPMiddlewareWithConfig(next http.Handler, config *HTTPMiddlewareConfig) http.Handler {
+	if config == nil {
+		config = NewDefaultHTTPMiddlewareConfig()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		if config.Skipped(r.URL.Path, rw.status) {
+			return
+		}
+		config.Log(WithContext(r.Context()), rw.status,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rw.status,
+			"latency_ms", float64(time.Since(start))/float64(time.Millisecond),
+			"bytes", rw.bytes,
+			"remote_ip", remoteIP(r.RemoteAddr),
+			"request_id", r.Header.Get(config.RequestIDHeaderName()),
+		)
+	})
+}
+
+// responseWriter records the status and the number of bytes written of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if !rw.wroteHeader {
+		rw.status = status
+		rw.wroteHeader = true
+	}
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *responseWriter) Write(p []byte) (int, error) {
+	rw.wroteHeader = true
+	n, err := rw.ResponseWriter.Write(p)
+	rw.bytes += n
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the flusher and hijacker of the wrapped writer
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// remoteIP strips the port from the remote address
+func remoteIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
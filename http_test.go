@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// captureEntries initializes the logger at DEBUG and returns a function listing the entries with
+// the message logged since
+func captureEntries(t *testing.T, message string) func() []Entry {
+	t.Helper()
+	var (
+		entries []Entry
+		mu      sync.Mutex
+	)
+	removeHook := AddHook(func(entry Entry) Entry {
+		if entry.Message == message {
+			mu.Lock()
+			entries = append(entries, entry)
+			mu.Unlock()
+		}
+		return entry
+	})
+	t.Cleanup(removeHook)
+
+	config := NewDefaultLoggerConfig()
+	config.LogMode = "DEBUG"
+	config.ConsoleSyncerDisabled = true
+	config.FileSyncerPath = filepath.Join(t.TempDir(), "app.log")
+	if err := Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitWithConfig(ZapLogger, config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reset() })
+
+	return func() []Entry {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]Entry(nil), entries...)
+	}
+}
+
+func serve(handler http.Handler, path string, header http.Header) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestHTTPMiddlewareRecordsStatusAndBytes(t *testing.T) {
+	entries := captureEntries(t, "http request")
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+			// Only the first status is sent
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, "hello")
+		case "/implicit":
+			_, _ = io.WriteString(w, "ok")
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	serve(handler, "/created", http.Header{"X-Request-Id": []string{"req-1"}})
+	serve(handler, "/implicit", nil)
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2: %v", len(got), got)
+	}
+	for i, want := range []struct {
+		path      string
+		status    int
+		bytes     int
+		requestID string
+	}{{"/created", http.StatusCreated, 5, "req-1"}, {"/implicit", http.StatusOK, 2, ""}} {
+		entry := got[i]
+		if field(entry, "path") != want.path || field(entry, "status") != want.status || field(entry, "bytes") != want.bytes || field(entry, "request_id") != want.requestID {
+			t.Errorf("entry %d = %v, want path %s, status %d, bytes %d and request id %q", i, entry.Fields, want.path, want.status, want.bytes, want.requestID)
+		}
+		if entry.Level != zapcore.InfoLevel {
+			t.Errorf("entry %d level = %v, want info", i, entry.Level)
+		}
+	}
+}
+
+func TestHTTPMiddlewareWithConfigLevels(t *testing.T) {
+	entries := captureEntries(t, "http request")
+	config := NewDefaultHTTPMiddlewareConfig()
+	config.LogMode = "DEBUG"
+	config.SkipPaths = []string{"/healthz"}
+	handler := HTTPMiddlewareWithConfig(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/broken":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}), config)
+
+	for _, path := range []string{"/healthz", "/ok", "/missing", "/broken"} {
+		serve(handler, path, nil)
+	}
+	got := entries()
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want 3 without the skipped path: %v", len(got), got)
+	}
+	for i, want := range []zapcore.Level{zapcore.DebugLevel, zapcore.DebugLevel, zapcore.ErrorLevel} {
+		if got[i].Level != want {
+			t.Errorf("%v logged at %v, want %v", field(got[i], "path"), got[i].Level, want)
+		}
+	}
+}
+
+func TestHTTPMiddlewareStructLiteralConfig(t *testing.T) {
+	entries := captureEntries(t, "http request")
+	handler := HTTPMiddlewareWithConfig(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), &HTTPMiddlewareConfig{})
+
+	serve(handler, "/ok", http.Header{"X-Request-Id": []string{"req-2"}})
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want every success logged with an unset rate: %v", len(got), got)
+	}
+	if id := field(got[0], "request_id"); id != "req-2" {
+		t.Errorf("request_id = %v, want req-2 from the default header", id)
+	}
+}
+
+func TestHTTPMiddlewareConfigSkipped(t *testing.T) {
+	config := &HTTPMiddlewareConfig{SkipPaths: []string{"/healthz"}, SuccessSampleRate: 1e-12}
+	if !config.Skipped("/healthz", http.StatusInternalServerError) {
+		t.Error("a skipped path was logged")
+	}
+	if !config.Skipped("/ok", http.StatusOK) {
+		t.Error("a success was logged at a sample rate of 1e-12")
+	}
+	if config.Skipped("/missing", http.StatusNotFound) || config.Skipped("/broken", http.StatusInternalServerError) {
+		t.Error("a failed request was sampled out")
+	}
+	for _, rate := range []float64{0, 1, -1, 2} {
+		config := &HTTPMiddlewareConfig{SuccessSampleRate: rate}
+		if config.Skipped("/ok", http.StatusOK) {
+			t.Errorf("a success was sampled out at a rate of %v, want all logged", rate)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Package logformat defines the levels and keys of the entries the logger writes, shared by the
+// logger and the logcat command reading them back.
+package logformat
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceLevel is the level below DEBUG, every lower level is encoded as trace as well
+const TraceLevel = zapcore.DebugLevel - 1
+
+// Keys of the host and service fields the logger adds to the entries
+const (
+	HostKey    = "host"
+	ServiceKey = "svc"
+)
+
+// LevelName returns the name of the level, trace for every level below DEBUG
+func LevelName(level zapcore.Level) string {
+	if level < zapcore.DebugLevel {
+		return "trace"
+	}
+	return level.String()
+}
+
+// ParseLevel parses a level name such as warn, accepting trace as well
+func ParseLevel(text string) (zapcore.Level, error) {
+	if strings.EqualFold(text, "trace") {
+		return TraceLevel, nil
+	}
+	var level zapcore.Level
+	err := level.UnmarshalText([]byte(text))
+	return level, err
+}
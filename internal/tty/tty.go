@@ -0,0 +1,15 @@
+// Package tty detects terminals, shared by the logger's TTY auto detection and logcat's colors.
+package tty
+
+import (
+	"os"
+
+	"github.com/mattn/go-isatty"
+)
+
+// IsTerminal reports whether the file is attached to a terminal, character devices such as
+// /dev/null are not
+func IsTerminal(f *os.File) bool {
+	fd := f.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceLevel is the level below DEBUG for chatty diagnostics, e.g. per packet or per row. Entries
+// of V(2) and above are logged at or below it.
+const TraceLevel = logformat.TraceLevel
+
+// lowercaseLevelEncoder encodes the level like zapcore.LowercaseLevelEncoder, levels below DEBUG
+// as trace
+func lowercaseLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if level < zapcore.DebugLevel {
+		enc.AppendString("trace")
+		return
+	}
+	zapcore.LowercaseLevelEncoder(level, enc)
+}
+
+// capitalLevelEncoder encodes the level like zapcore.CapitalLevelEncoder, levels below DEBUG as
+// TRACE
+func capitalLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if level < zapcore.DebugLevel {
+		enc.AppendString("TRACE")
+		return
+	}
+	zapcore.CapitalLevelEncoder(level, enc)
+}
+
+// capitalColorLevelEncoder encodes the level like zapcore.CapitalColorLevelEncoder, levels below
+// DEBUG as a cyan TRACE
+func capitalColorLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if level < zapcore.DebugLevel {
+		enc.AppendString("\x1b[36mTRACE\x1b[0m")
+		return
+	}
+	zapcore.CapitalColorLevelEncoder(level, enc)
+}
+
+// VerbosityLevel returns the level of a V style verbosity: 0 is INFO, 1 DEBUG, 2 TRACE and every
+// further verbosity one level below
+func VerbosityLevel(verbosity int) zapcore.Level {
+	if verbosity < 0 {
+		verbosity = 0
+	}
+	// Levels are int8
+	if verbosity > 127 {
+		verbosity = 127
+	}
+	return zapcore.InfoLevel - zapcore.Level(verbosity)
+}
+
+// SetVerbosity changes the level of the running logger so V(n) entries up to the verbosity are
+// logged, e.g. 3 for V(3). It overrides the log mode like SetLogMode does.
+func SetVerbosity(verbosity int) error {
+	if verbosity < 0 {
+		return fmt.Errorf("invalid verbosity %d: must not be negative", verbosity)
+	}
+	z := currentLogger()
+	if z == nil {
+		return errors.New("logger is not initialized")
+	}
+	z.build.level.SetLevel(VerbosityLevel(verbosity))
+	return nil
+}
+
+// Verbose logs at a verbosity level, it is obtained with V
+type Verbose struct {
+	level zapcore.Level
+}
+
+// V returns the global logger at the verbosity, klog style: V(0) logs at INFO, V(1) at DEBUG, V(2)
+// at TRACE and every further verbosity one level below. Its entries are only logged once the
+// verbosity is enabled through LogMode, Verbosity or SetVerbosity, so chatty diagnostics can stay
+// in the code permanently:
+//
+//	logger.V(3).Info("packet received", "size", n)
+func V(verbosity int) Verbose {
+	return Verbose{level: VerbosityLevel(verbosity)}
+}
+
+// Enabled reports whether the entries of the verbosity are logged, to skip computing costly fields
+func (v Verbose) Enabled() bool {
+	z := currentLogger()
+	return z != nil && v.level >= z.sugar.Level()
+}
+
+// Info logs the message with the key value fields at the verbosity
+func (v Verbose) Info(message string, fields ...interface{}) {
+	if z := currentLogger(); z != nil {
+		z.log(v.level, message, fields)
+	}
+}
+
+// logVerbose logs below DEBUG, which the sugared logger has no methods for
+func (z *zapLogger) logVerbose(level zapcore.Level, message string, fields []interface{}) {
+	if level < z.sugar.Level() {
+		return
+	}
+	// The caller skip of the sugared logger does not account for this frame
+	if ce := z.sugar.Desugar().WithOptions(zap.AddCallerSkip(1)).Check(level, message); ce != nil {
+		ce.Write(keyValueFields(fields)...)
+	}
+}
+
+// keyValueFields converts key value pairs to zap fields like the sugared logger does
+func keyValueFields(fields []interface{}) []zap.Field {
+	zapFields := make([]zap.Field, 0, len(fields)/2)
+	for i := 0; i < len(fields); i++ {
+		if field, ok := fields[i].(zap.Field); ok {
+			zapFields = append(zapFields, field)
+			continue
+		}
+		key, ok := fields[i].(string)
+		if !ok || i == len(fields)-1 {
+			zapFields = append(zapFields, zap.Any("ignored", fields[i]))
+			continue
+		}
+		zapFields = append(zapFields, zap.Any(key, fields[i+1]))
+		i++
+	}
+	return zapFields
+}
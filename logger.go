@@ -1,7 +1,13 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
 	"sync"
+
+	"go.uber.org/zap"
 )
 
 // ILogger is the interface for the logger
@@ -17,18 +23,38 @@ type ILogger interface {
 
 // LoggerConfig is the config for the logger
 type LoggerConfig struct {
-	ServiceName           string // to set the service name (default: "")
-	LogMode               string // INFO, DEBUG, WARN, ERROR, FATAL (default: INFO)
-	JsonEncoderDisabled   bool   // to disable the json encoding of logs (default: false)
-	ConsoleSyncerDisabled bool   // to disable the std out based logging of logs (default: false)
-	FileSyncerDisabled    bool   // to disable file based logging of logs (default: false)
-	SocketLoggingEnabled  bool   // to enable socket logging of logs (default: false)
-	SocketTimeout         int    // to set the timeout for the socket connection (default: 10)
-	FileSyncerPath        string // to set the path of the file to be logged (default: "")
-	FileSyncerMaxSize     int    // to set the max size of the file to be logged (default: 100)
-	FileSyncerMaxBackups  int    // to set the max backups of the file to be logged (default: 10)
-	FileSyncerMaxAge      int    // to set the max age of the file to be logged (default: 30)
-	FileSyncerCompress    bool   // to set the compress of the file to be logged (default: false)
+	ServiceName           string       // to set the service name (default: "")
+	LogMode               string       // TRACE, DEBUG, INFO, WARN, ERROR, FATAL (default: INFO)
+	JsonEncoderDisabled   bool         // to disable the json encoding of logs (default: false)
+	ConsoleSyncerDisabled bool         // to disable the std out based logging of logs (default: false)
+	FileSyncerDisabled    bool         // to disable file based logging of logs (default: false)
+	SocketLoggingEnabled  bool         // to enable socket logging of logs (default: false)
+	SocketTimeout         int          // to set the timeout for the socket connection (default: 10)
+	FileSyncerPath        string       // to set the path of the file to be logged (default: "")
+	FileSyncerMaxSize     int          // to set the max size of the file to be logged (default: 100)
+	FileSyncerMaxBackups  int          // to set the max backups of the file to be logged (default: 10)
+	FileSyncerMaxAge      int          // to set the max age of the file to be logged (default: 30)
+	FileSyncerCompress    bool         // to set the compress of the file to be logged (default: false)
+	FileSyncerReopenOnHUP bool         // to reopen the log file on SIGHUP for external logrotate (default: false)
+	CallerEnabled         bool         // to add the caller file and line to logs (default: false)
+	ColorEnabled          bool         // to colorize the level of logs, only applies to the console encoder (default: false)
+	SamplingEnabled       bool         // to enable sampling of repetitive logs (default: false)
+	SamplingInitial       int          // to set the number of identical logs per second logged before sampling (default: 100)
+	SamplingThereafter    int          // to set that every Nth identical log is logged after SamplingInitial (default: 100)
+	TTYAutoDetectEnabled  bool         // to use the console encoder and skip file logging when stdout is a terminal, json otherwise (default: false)
+	AuditLoggingEnabled   bool         // to enable the dedicated audit log file, audit logs go to std out otherwise (default: false)
+	AuditFilePath         string       // to set the path of the audit log file (default: "")
+	AuditFileMaxSize      int          // to set the max size of the audit log file (default: 100)
+	AuditFileMaxBackups   int          // to set the max backups of the audit log file, 0 retains all (default: 0)
+	AuditFileMaxAge       int          // to set the max age in days of the audit log file (default: 365)
+	AuditFileCompress     bool         // to set the compress of the audit log file (default: true)
+	TenantRoutingEnabled  bool         // to write the logs of each tenant to its own file in TenantFileDir instead of the shared sinks (default: false)
+	TenantFileDir         string       // to set the directory of the per tenant log files (default: "")
+	TenantMaxOpenFiles    int          // to set the max tenant log files kept open, the least recently used is closed beyond it, 0 keeps all (default: 100)
+	Hostname              string       // to set the host field of the logs, the OS hostname if empty (default: "")
+	RecentEntriesSize     int          // to keep the last N entries in memory for DebugLogsHandler, 0 disables it (default: 0)
+	Verbosity             int          // to log the V(n) entries up to the verbosity, e.g. 3, overrides LogMode when above 0 (default: 0)
+	ZapOptions            []zap.Option // extra zap options applied last to the logger, e.g. zap.AddStacktrace or zap.WrapCore (default: nil)
 }
 
 // NewDefaultLoggerConfig creates a new default logger config
@@ -46,12 +72,119 @@ func NewDefaultLoggerConfig() *LoggerConfig {
 		FileSyncerMaxBackups:  10,
 		FileSyncerMaxAge:      30,
 		FileSyncerCompress:    false,
+		FileSyncerReopenOnHUP: false,
+		CallerEnabled:         false,
+		ColorEnabled:          false,
+		SamplingEnabled:       false,
+		SamplingInitial:       defaultSamplingInitial,
+		SamplingThereafter:    defaultSamplingThereafter,
+		TTYAutoDetectEnabled:  false,
+		AuditLoggingEnabled:   false,
+		AuditFilePath:         "",
+		AuditFileMaxSize:      100,
+		AuditFileMaxBackups:   0,
+		AuditFileMaxAge:       365,
+		AuditFileCompress:     true,
+		TenantRoutingEnabled:  false,
+		TenantFileDir:         "",
+		TenantMaxOpenFiles:    100,
+		Hostname:              "",
+		RecentEntriesSize:     0,
+		Verbosity:             0,
+		ZapOptions:            nil,
 	}
 }
 
+// DevelopmentConfig creates a logger config suited for local development: human readable colored
+// console output at DEBUG with caller information and no file logging
+func DevelopmentConfig() *LoggerConfig {
+	config := NewDefaultLoggerConfig()
+	config.LogMode = "DEBUG"
+	config.JsonEncoderDisabled = true
+	config.FileSyncerDisabled = true
+	config.CallerEnabled = true
+	config.ColorEnabled = true
+	return config
+}
+
+// ProductionConfig creates a logger config suited for production: JSON output at INFO with
+// sampling and rotated file logging in addition to the console
+func ProductionConfig() *LoggerConfig {
+	config := NewDefaultLoggerConfig()
+	config.FileSyncerPath = "logs/app.log"
+	config.FileSyncerCompress = true
+	config.SamplingEnabled = true
+	return config
+}
+
+// logModes are the accepted values of LoggerConfig.LogMode, matched case-insensitively
+var logModes = map[string]bool{"TRACE": true, "DEBUG": true, "INFO": true, "WARN": true, "ERROR": true, "FATAL": true}
+
+func isLogMode(mode string) bool {
+	return logModes[strings.ToUpper(mode)]
+}
+
+// Validate checks the config for values that would otherwise silently result in missing logs
+func (c *LoggerConfig) Validate() error {
+	var errs []error
+	if c.LogMode != "" && !isLogMode(c.LogMode) {
+		errs = append(errs, fmt.Errorf("invalid LogMode %q: must be one of TRACE, DEBUG, INFO, WARN, ERROR, FATAL", c.LogMode))
+	}
+	if c.SocketTimeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid SocketTimeout %d: must not be negative", c.SocketTimeout))
+	}
+	if c.SocketLoggingEnabled && c.ServiceName == "" {
+		errs = append(errs, errors.New("ServiceName must be set when SocketLoggingEnabled is true"))
+	}
+	if c.ConsoleSyncerDisabled && c.FileSyncerDisabled && !c.SocketLoggingEnabled {
+		errs = append(errs, errors.New("no sink enabled: console and file syncers are disabled and socket logging is not enabled"))
+	}
+	if c.FileSyncerMaxSize < 0 || c.FileSyncerMaxBackups < 0 || c.FileSyncerMaxAge < 0 {
+		errs = append(errs, errors.New("FileSyncerMaxSize, FileSyncerMaxBackups and FileSyncerMaxAge must not be negative"))
+	}
+	if c.AuditLoggingEnabled && c.AuditFilePath == "" {
+		errs = append(errs, errors.New("AuditFilePath must be set when AuditLoggingEnabled is true"))
+	}
+	if c.TenantRoutingEnabled && c.TenantFileDir == "" {
+		errs = append(errs, errors.New("TenantFileDir must be set when TenantRoutingEnabled is true"))
+	}
+	if c.TenantMaxOpenFiles < 0 {
+		errs = append(errs, fmt.Errorf("invalid TenantMaxOpenFiles %d: must not be negative", c.TenantMaxOpenFiles))
+	}
+	if c.TTYAutoDetectEnabled && c.ConsoleSyncerDisabled {
+		errs = append(errs, errors.New("ConsoleSyncerDisabled must be false when TTYAutoDetectEnabled is true: on a terminal file logging is skipped, leaving no sink"))
+	}
+	if c.SamplingEnabled && (c.SamplingInitial <= 0 || c.SamplingThereafter <= 0) {
+		errs = append(errs, errors.New("SamplingInitial and SamplingThereafter must be positive when SamplingEnabled is true"))
+	}
+	if c.Verbosity < 0 {
+		errs = append(errs, fmt.Errorf("invalid Verbosity %d: must not be negative", c.Verbosity))
+	}
+	if c.RecentEntriesSize < 0 {
+		errs = append(errs, fmt.Errorf("invalid RecentEntriesSize %d: must not be negative", c.RecentEntriesSize))
+	}
+	return errors.Join(errs...)
+}
+
+// SetLogMode changes the level of the running logger, mode is one of TRACE, DEBUG, INFO, WARN, ERROR, FATAL
+func SetLogMode(mode string) error {
+	if !isLogMode(mode) {
+		return fmt.Errorf("invalid log mode %q: must be one of TRACE, DEBUG, INFO, WARN, ERROR, FATAL", mode)
+	}
+	z := currentLogger()
+	if z == nil {
+		return errors.New("logger is not initialized")
+	}
+	z.build.level.SetLevel(parseLogMode(mode))
+	return nil
+}
+
 var (
-	Logger ILogger
+	// Logger forwards to the global logger and stays valid across re-initializations, it is
+	// equivalent to L()
+	Logger ILogger = globalLogger{}
 	once   sync.Once
+	initMu sync.Mutex
 )
 
 // LoggerType is the type of logger
@@ -62,21 +195,33 @@ const (
 )
 
 // init initializes the logger with default config
-func Init() {
-	InitWithConfig(ZapLogger, NewDefaultLoggerConfig())
+func Init() error {
+	return InitWithConfig(ZapLogger, NewDefaultLoggerConfig())
 }
 
-// init initializes the logger with config
-func InitWithConfig(loggerType LoggerType, config *LoggerConfig) {
+// init initializes the logger with config. If the config is invalid the error is printed to std
+// err and returned, and the logger is initialized with the default config so logs are not lost.
+func InitWithConfig(loggerType LoggerType, config *LoggerConfig) error {
 	if config == nil {
 		config = NewDefaultLoggerConfig()
 	}
+	var validationErr error
+	if err := config.Validate(); err != nil {
+		validationErr = fmt.Errorf("invalid logger config: %w", err)
+		fmt.Fprintf(os.Stderr, "%s, falling back to the default config\n", validationErr)
+		defaultConfig := NewDefaultLoggerConfig()
+		defaultConfig.ServiceName = config.ServiceName
+		config = defaultConfig
+	}
 	switch loggerType {
 	case ZapLogger:
+		initMu.Lock()
+		defer initMu.Unlock()
 		once.Do(func() {
 			initializeLoggerWithZapLogger(config)
 		})
 	default:
-		Logger.Fatal("Invalid logger type", "loggerType", loggerType)
+		return fmt.Errorf("invalid logger type %q", loggerType)
 	}
+	return validationErr
 }
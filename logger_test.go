@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *LoggerConfig)
+		wantErr string
+	}{
+		{"default", func(c *LoggerConfig) {}, ""},
+		{"lowercase log mode", func(c *LoggerConfig) { c.LogMode = "trace" }, ""},
+		{"empty log mode", func(c *LoggerConfig) { c.LogMode = "" }, ""},
+		{"invalid log mode", func(c *LoggerConfig) { c.LogMode = "LOUD" }, `invalid LogMode "LOUD"`},
+		{"negative socket timeout", func(c *LoggerConfig) { c.SocketTimeout = -1 }, "invalid SocketTimeout -1"},
+		{"socket without service name", func(c *LoggerConfig) { c.SocketLoggingEnabled = true }, "ServiceName must be set"},
+		{"socket with service name", func(c *LoggerConfig) { c.SocketLoggingEnabled, c.ServiceName = true, "orders" }, ""},
+		{"no sink", func(c *LoggerConfig) { c.ConsoleSyncerDisabled, c.FileSyncerDisabled = true, true }, "no sink enabled"},
+		{"socket sink only", func(c *LoggerConfig) {
+			c.ConsoleSyncerDisabled, c.FileSyncerDisabled, c.SocketLoggingEnabled, c.ServiceName = true, true, true, "orders"
+		}, ""},
+		{"negative file max size", func(c *LoggerConfig) { c.FileSyncerMaxSize = -1 }, "FileSyncerMaxSize"},
+		{"negative file max backups", func(c *LoggerConfig) { c.FileSyncerMaxBackups = -1 }, "FileSyncerMaxBackups"},
+		{"negative file max age", func(c *LoggerConfig) { c.FileSyncerMaxAge = -1 }, "FileSyncerMaxAge"},
+		{"audit without path", func(c *LoggerConfig) { c.AuditLoggingEnabled = true }, "AuditFilePath must be set"},
+		{"tenants without dir", func(c *LoggerConfig) { c.TenantRoutingEnabled = true }, "TenantFileDir must be set"},
+		{"negative tenant max open files", func(c *LoggerConfig) { c.TenantMaxOpenFiles = -1 }, "invalid TenantMaxOpenFiles -1"},
+		{"tty auto detect without console", func(c *LoggerConfig) { c.TTYAutoDetectEnabled, c.ConsoleSyncerDisabled = true, true }, "ConsoleSyncerDisabled must be false"},
+		{"sampling without initial", func(c *LoggerConfig) { c.SamplingEnabled, c.SamplingInitial = true, 0 }, "SamplingInitial and SamplingThereafter must be positive"},
+		{"sampling without thereafter", func(c *LoggerConfig) { c.SamplingEnabled, c.SamplingThereafter = true, 0 }, "SamplingInitial and SamplingThereafter must be positive"},
+		{"negative verbosity", func(c *LoggerConfig) { c.Verbosity = -1 }, "invalid Verbosity -1"},
+		{"negative recent entries size", func(c *LoggerConfig) { c.RecentEntriesSize = -1 }, "invalid RecentEntriesSize -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewDefaultLoggerConfig()
+			tt.modify(config)
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateJoinsErrors(t *testing.T) {
+	config := NewDefaultLoggerConfig()
+	config.LogMode = "LOUD"
+	config.Verbosity = -1
+	err := config.Validate()
+	if err == nil || !strings.Contains(err.Error(), "LogMode") || !strings.Contains(err.Error(), "Verbosity") {
+		t.Errorf("Validate() = %v, want both the LogMode and the Verbosity errors", err)
+	}
+}
+
+func TestInitWithConfigFallsBackToDefaultConfig(t *testing.T) {
+	if err := Reset(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reset() })
+
+	config := NewDefaultLoggerConfig()
+	config.ServiceName = "orders"
+	config.LogMode = "LOUD"
+	config.ConsoleSyncerDisabled = true
+	config.FileSyncerDisabled = true
+
+	stderr := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = w
+	initErr := InitWithConfig(ZapLogger, config)
+	os.Stderr = stderr
+	w.Close()
+	printed, _ := io.ReadAll(r)
+
+	if initErr == nil || !strings.Contains(initErr.Error(), `invalid LogMode "LOUD"`) {
+		t.Errorf("InitWithConfig() = %v, want the validation error", initErr)
+	}
+	if !strings.Contains(string(printed), "falling back to the default config") {
+		t.Errorf("std err = %q, want the fallback reported", printed)
+	}
+	z := currentLogger()
+	if z == nil {
+		t.Fatal("logger is not initialized after the fallback")
+	}
+	if got := z.build.config; got.LogMode != "INFO" || got.ServiceName != "orders" || got.ConsoleSyncerDisabled {
+		t.Errorf("initialized with LogMode %q, ServiceName %q and ConsoleSyncerDisabled %v, want the default config keeping the service name",
+			got.LogMode, got.ServiceName, got.ConsoleSyncerDisabled)
+	}
+}
+
+func TestInitWithConfigRejectsUnknownType(t *testing.T) {
+	if err := InitWithConfig("slog", NewDefaultLoggerConfig()); err == nil {
+		t.Errorf("InitWithConfig() = %v, want an invalid logger type error", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Package loggertest captures the output of the logger deterministically, with a fixed time and
+// host, and compares it against golden files so the log schema can be locked down by tests.
+//
+// Run the tests with LOGGERTEST_UPDATE=true to write the golden files from the current output.
+package loggertest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	logger "github.com/piyushkumar96/generic-logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// FixedHost is the host of captured entries
+	FixedHost = "loggertest-host"
+	// updateEnv is the environment variable enabling the update of golden files
+	updateEnv = "LOGGERTEST_UPDATE"
+)
+
+// FixedTime is the time of captured entries
+var FixedTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// TestingT is the subset of testing.TB used by the golden file helpers
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+}
+
+// Capture creates a logger encoding entries like the config would, with a fixed time and host,
+// into the returned buffer. Caller information is disabled since it depends on the call site.
+func Capture(config *logger.LoggerConfig) (logger.ILogger, *bytes.Buffer, error) {
+	config = deterministicConfig(config)
+	buf := &bytes.Buffer{}
+	l, err := logger.NewLoggerWithWriter(config, buf, zap.WithClock(fixedClock{}))
+	return l, buf, err
+}
+
+// CaptureAudit creates an audit logger encoding entries like the config would, with a fixed time
+// and host, into the returned buffer
+func CaptureAudit(config *logger.LoggerConfig) (logger.IAuditLogger, *bytes.Buffer) {
+	config = deterministicConfig(config)
+	buf := &bytes.Buffer{}
+	return logger.NewAuditLoggerWithWriter(config, buf, zap.WithClock(fixedClock{})), buf
+}
+
+// AssertGolden compares got with the golden file testdata/<name>.golden, with LOGGERTEST_UPDATE
+// set to true the golden file is written from got instead
+func AssertGolden(t TestingT, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if update, _ := strconv.ParseBool(os.Getenv(updateEnv)); update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s, run with %s=true to create it: %v", path, updateEnv, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("log output does not match golden file %s, run with %s=true to update it\n--- got\n%s\n--- want\n%s", path, updateEnv, got, want)
+	}
+}
+
+func deterministicConfig(config *logger.LoggerConfig) *logger.LoggerConfig {
+	if config == nil {
+		config = logger.NewDefaultLoggerConfig()
+	}
+	c := *config
+	c.Hostname = FixedHost
+	c.CallerEnabled = false
+	c.ColorEnabled = false
+	return &c
+}
+
+// fixedClock always tells FixedTime
+type fixedClock struct{}
+
+func (fixedClock) Now() time.Time {
+	return FixedTime
+}
+
+func (fixedClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
@@ -0,0 +1,47 @@
+package loggertest
+
+import (
+	"errors"
+	"testing"
+
+	logger "github.com/piyushkumar96/generic-logger"
+)
+
+func TestCaptureJSON(t *testing.T) {
+	config := logger.ProductionConfig()
+	config.ServiceName = "orders"
+	log, buf, err := Capture(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Debug("not logged at INFO")
+	log.Info("order created", "order_id", 42, "meta", map[string]interface{}{"channel": "web"})
+	log.Warn("order delayed", "order_id", 42, "delay_ms", 1500.5)
+	log.Error("order failed", "order_id", 42, "err", errors.New("card declined").Error())
+	AssertGolden(t, "capture_json", buf.Bytes())
+}
+
+func TestCaptureConsole(t *testing.T) {
+	config := logger.DevelopmentConfig()
+	config.ServiceName = "orders"
+	log, buf, err := Capture(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Debug("cache miss", "key", "order:42")
+	log.Info("order created", "order_id", 42)
+	AssertGolden(t, "capture_console", buf.Bytes())
+}
+
+func TestCaptureAudit(t *testing.T) {
+	config := logger.NewDefaultLoggerConfig()
+	config.ServiceName = "orders"
+	audit, buf := CaptureAudit(config)
+	if err := audit.Log(logger.AuditEntry{Actor: "user-1", Action: "delete", Resource: "invoice/42", Outcome: "success"}, "reason", "duplicate"); err != nil {
+		t.Fatal(err)
+	}
+	if err := audit.Log(logger.AuditEntry{Actor: "user-1", Action: "delete"}); err == nil {
+		t.Error("audit entry without resource and outcome was accepted")
+	}
+	AssertGolden(t, "capture_audit", buf.Bytes())
+}
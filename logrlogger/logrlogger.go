@@ -0,0 +1,85 @@
+// Package logrlogger provides a logr.LogSink writing through the generic logger, so client-go,
+// controller-runtime and other logr and klog consumers write to the same sinks.
+package logrlogger
+
+import (
+	"github.com/go-logr/logr"
+	logger "github.com/piyushkumar96/generic-logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// Sink implements logr.LogSink through an ILogger. Verbosities map to levels like logger.V does,
+// V(1) is logged at DEBUG, V(2) at TRACE and every further verbosity one level below, and the
+// logger names are joined into the component field.
+type Sink struct {
+	logger logger.ILogger
+	name   string
+	values []interface{}
+}
+
+// New creates a logr.Logger writing through the logger, nil uses the global logger. Pass it to
+// klog.SetLogger to route klog as well.
+func New(l logger.ILogger) logr.Logger {
+	return logr.New(&Sink{logger: l})
+}
+
+func (s *Sink) current() logger.ILogger {
+	if s.logger != nil {
+		return s.logger
+	}
+	return logger.L()
+}
+
+// Init implements logr.LogSink
+func (s *Sink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink
+func (s *Sink) Enabled(level int) bool {
+	return logger.LevelEnabled(s.current(), logrLevel(level))
+}
+
+// Info implements logr.LogSink
+func (s *Sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	logger.LogAt(s.current(), logrLevel(level), msg, s.fields(keysAndValues)...)
+}
+
+// Error implements logr.LogSink
+func (s *Sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	fields := s.fields(keysAndValues)
+	if err != nil {
+		fields = append(fields, "err", err.Error())
+	}
+	s.current().Error(msg, fields...)
+}
+
+// WithValues implements logr.LogSink
+func (s *Sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	sink := *s
+	sink.values = append(append([]interface{}(nil), s.values...), keysAndValues...)
+	return &sink
+}
+
+// WithName implements logr.LogSink
+func (s *Sink) WithName(name string) logr.LogSink {
+	sink := *s
+	if sink.name == "" {
+		sink.name = name
+	} else {
+		sink.name += "/" + name
+	}
+	return &sink
+}
+
+func (s *Sink) fields(keysAndValues []interface{}) []interface{} {
+	fields := make([]interface{}, 0, len(s.values)+len(keysAndValues)+2)
+	if s.name != "" {
+		fields = append(fields, logger.ComponentKey, s.name)
+	}
+	fields = append(fields, s.values...)
+	return append(fields, keysAndValues...)
+}
+
+// logrLevel maps a logr verbosity to the level logger.V logs it at
+func logrLevel(level int) zapcore.Level {
+	return logger.VerbosityLevel(level)
+}
@@ -0,0 +1,61 @@
+package logrlogger
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	logger "github.com/piyushkumar96/generic-logger"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestVerbosityLevels(t *testing.T) {
+	var mu sync.Mutex
+	levels := map[string]zapcore.Level{}
+	removeHook := logger.AddHook(func(entry logger.Entry) logger.Entry {
+		mu.Lock()
+		levels[entry.Message] = entry.Level
+		mu.Unlock()
+		return entry
+	})
+	t.Cleanup(removeHook)
+
+	config := logger.NewDefaultLoggerConfig()
+	config.LogMode = "DEBUG"
+	config.ConsoleSyncerDisabled = true
+	config.FileSyncerPath = filepath.Join(t.TempDir(), "app.log")
+	if err := logger.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := logger.InitWithConfig(logger.ZapLogger, config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = logger.Reset() })
+
+	log := New(nil)
+	if !log.V(1).Enabled() || log.V(2).Enabled() {
+		t.Errorf("at DEBUG got V(1) enabled %v and V(2) enabled %v, want true and false", log.V(1).Enabled(), log.V(2).Enabled())
+	}
+	if err := logger.SetVerbosity(3); err != nil {
+		t.Fatal(err)
+	}
+	if !log.V(3).Enabled() || log.V(4).Enabled() {
+		t.Errorf("at verbosity 3 got V(3) enabled %v and V(4) enabled %v, want true and false", log.V(3).Enabled(), log.V(4).Enabled())
+	}
+
+	log.Info("v0")
+	log.V(1).Info("v1")
+	log.V(3).Info("v3")
+	log.V(4).Info("v4")
+	want := map[string]zapcore.Level{"v0": zapcore.InfoLevel, "v1": zapcore.DebugLevel, "v3": logger.TraceLevel - 1}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(levels) != len(want) {
+		t.Errorf("logged %v, want %v", levels, want)
+	}
+	for message, level := range want {
+		if got, ok := levels[message]; !ok || got != level {
+			t.Errorf("%s logged at %v (%v), want %v", message, got, ok, level)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Names of the sinks the logger writes to
+const (
+	sinkConsole = "console"
+	sinkFile    = "file"
+	sinkSocket  = "socket"
+	sinkAudit   = "audit"
+)
+
+var (
+	sinkNames = []string{sinkConsole, sinkFile, sinkSocket, sinkAudit}
+
+	// entryCounts counts the written entries per level, indexed by level - TraceLevel, every level
+	// below DEBUG is counted as trace
+	entryCounts [zapcore.FatalLevel - TraceLevel + 1]atomic.Uint64
+	// sinkErrorCounts counts the failed writes per sink
+	sinkErrorCounts = map[string]*atomic.Uint64{
+		sinkConsole: new(atomic.Uint64),
+		sinkFile:    new(atomic.Uint64),
+		sinkSocket:  new(atomic.Uint64),
+		sinkAudit:   new(atomic.Uint64),
+	}
+	// sinkBytesCounts counts the bytes successfully written per sink
+	sinkBytesCounts = map[string]*atomic.Uint64{
+		sinkConsole: new(atomic.Uint64),
+		sinkFile:    new(atomic.Uint64),
+		sinkSocket:  new(atomic.Uint64),
+		sinkAudit:   new(atomic.Uint64),
+	}
+)
+
+// countEntry is a zap hook counting every entry written by the logger
+func countEntry(entry zapcore.Entry) error {
+	level := entry.Level
+	if level < TraceLevel {
+		level = TraceLevel
+	}
+	if level <= zapcore.FatalLevel {
+		entryCounts[level-TraceLevel].Add(1)
+	}
+	return nil
+}
+
+func recordSinkError(sink string, err error) {
+	sinkErrorCounts[sink].Add(1)
+	sinkStates[sink].failed(err)
+}
+
+func recordSinkOK(sink string) {
+	sinkStates[sink].ok()
+}
+
+func recordSinkBytes(sink string, n int) {
+	sinkBytesCounts[sink].Add(uint64(n))
+}
+
+// countingWriteSyncer records the written bytes and failed writes of the wrapped sink
+type countingWriteSyncer struct {
+	zapcore.WriteSyncer
+	sink string
+}
+
+func newCountingWriteSyncer(sink string, ws zapcore.WriteSyncer) zapcore.WriteSyncer {
+	return &countingWriteSyncer{WriteSyncer: ws, sink: sink}
+}
+
+func (c *countingWriteSyncer) Write(p []byte) (int, error) {
+	n, err := c.WriteSyncer.Write(p)
+	recordSinkBytes(c.sink, n)
+	if err != nil {
+		recordSinkError(c.sink, err)
+		return n, &sinkError{sink: c.sink, err: err}
+	}
+	recordSinkOK(c.sink)
+	return n, nil
+}
@@ -0,0 +1,37 @@
+// Package promlogger provides a prometheus collector exposing the generic logger's internal
+// counters, so only the applications registering it link the prometheus client.
+package promlogger
+
+import (
+	logger "github.com/piyushkumar96/generic-logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type collector struct {
+	entriesDesc *prometheus.Desc
+	errorsDesc  *prometheus.Desc
+}
+
+// NewMetricsCollector creates a prometheus collector exposing logger_entries_total by level and
+// logger_errors_total by sink, register it to alert on error rate spikes and log shipping failures
+func NewMetricsCollector() prometheus.Collector {
+	return &collector{
+		entriesDesc: prometheus.NewDesc("logger_entries_total", "Number of log entries written, by level.", []string{"level"}, nil),
+		errorsDesc:  prometheus.NewDesc("logger_errors_total", "Number of failed log writes, by sink.", []string{"sink"}, nil),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.entriesDesc
+	ch <- c.errorsDesc
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	stats := logger.Stats()
+	for level, count := range stats.Entries {
+		ch <- prometheus.MustNewConstMetric(c.entriesDesc, prometheus.CounterValue, float64(count), level)
+	}
+	for sink, count := range stats.WriteErrors {
+		ch <- prometheus.MustNewConstMetric(c.errorsDesc, prometheus.CounterValue, float64(count), sink)
+	}
+}
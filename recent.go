@@ -0,0 +1,264 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"go.uber.org/zap/zapcore"
+)
+
+// subscriberBufferSize is the number of entries buffered for a live tail, a subscriber not keeping
+// up misses the entries beyond it
+const subscriberBufferSize = 256
+
+// recentEntry is a JSON encoded entry kept in memory for the debug logs handler
+type recentEntry struct {
+	level zapcore.Level
+	line  []byte
+}
+
+// entryRing keeps the last entries logged and forwards new ones to the live tail subscribers
+type entryRing struct {
+	mu          sync.Mutex
+	entries     []recentEntry
+	next        int
+	full        bool
+	subscribers map[chan recentEntry]struct{}
+}
+
+// recentEntries is kept across initializations so live tails survive a re-initialization
+var recentEntries = &entryRing{subscribers: map[chan recentEntry]struct{}{}}
+
+// resize sets the number of entries kept, dropping the kept ones
+func (r *entryRing) resize(size int) {
+	if size < 0 {
+		size = 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = make([]recentEntry, size)
+	r.next = 0
+	r.full = false
+}
+
+func (r *entryRing) enabled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries) > 0
+}
+
+func (r *entryRing) add(entry recentEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	for ch := range r.subscribers {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// snapshot returns the kept entries, oldest first
+func (r *entryRing) snapshot() []recentEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked()
+}
+
+func (r *entryRing) snapshotLocked() []recentEntry {
+	if !r.full {
+		return append([]recentEntry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]recentEntry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// subscribe returns the kept entries and a channel receiving the entries logged from then on
+func (r *entryRing) subscribe() (kept []recentEntry, ch chan recentEntry, cancel func()) {
+	ch = make(chan recentEntry, subscriberBufferSize)
+	r.mu.Lock()
+	kept = r.snapshotLocked()
+	r.subscribers[ch] = struct{}{}
+	r.mu.Unlock()
+	return kept, ch, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		delete(r.subscribers, ch)
+	}
+}
+
+// recentCore encodes the entries as JSON into the ring, whatever the encoding of the other sinks
+type recentCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	ring    *entryRing
+}
+
+// withRecentCore tees the core with one keeping the entries for the debug logs handler, when enabled
+func withRecentCore(core zapcore.Core, encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) zapcore.Core {
+	if !recentEntries.enabled() {
+		return core
+	}
+	return zapcore.NewTee(core, &recentCore{LevelEnabler: level, encoder: zapcore.NewJSONEncoder(encoderConfig), ring: recentEntries})
+}
+
+// Level returns the minimum enabled level, including levels below DEBUG
+func (c *recentCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+func (c *recentCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &recentCore{LevelEnabler: c.LevelEnabler, encoder: encoder, ring: c.ring}
+}
+
+func (c *recentCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *recentCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return err
+	}
+	line := bytes.TrimRight(buf.Bytes(), "\n")
+	c.ring.add(recentEntry{level: entry.Level, line: append([]byte(nil), line...)})
+	buf.Free()
+	return nil
+}
+
+func (c *recentCore) Sync() error {
+	return nil
+}
+
+// DebugLogsHandler returns an admin handler streaming the last RecentEntriesSize entries followed
+// by the live tail, so the logs of a pod can be watched without access to the log pipeline. It
+// must only be exposed on an admin port or behind authentication.
+//
+// The stream is SSE when the request accepts text/event-stream or format=sse is set, NDJSON
+// otherwise. Query parameters:
+//
+//	level=warn    only entries at the level or above
+//	follow=false  end the response after the kept entries instead of tailing
+//	key=value     any other parameter only keeps entries whose field equals the value
+func DebugLogsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !recentEntries.enabled() {
+			http.Error(w, "recent entries are disabled, set RecentEntriesSize", http.StatusNotFound)
+			return
+		}
+		query := r.URL.Query()
+		// Every level, including those below DEBUG, unless set
+		minLevel := zapcore.Level(math.MinInt8)
+		if level := query.Get("level"); level != "" {
+			var err error
+			if minLevel, err = logformat.ParseLevel(level); err != nil {
+				http.Error(w, fmt.Sprintf("invalid level %q", level), http.StatusBadRequest)
+				return
+			}
+		}
+		follow := true
+		if f := query.Get("follow"); f != "" {
+			var err error
+			if follow, err = strconv.ParseBool(f); err != nil {
+				http.Error(w, fmt.Sprintf("invalid follow %q", f), http.StatusBadRequest)
+				return
+			}
+		}
+		sse := query.Get("format") == "sse" || strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+		where := map[string]string{}
+		for key, values := range query {
+			if key != "level" && key != "follow" && key != "format" && len(values) > 0 {
+				where[key] = values[0]
+			}
+		}
+
+		// The controller finds the flusher of wrapped response writers, e.g. of HTTPMiddleware
+		controller := http.NewResponseController(w)
+		if sse {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		write := func(entry recentEntry) error {
+			if entry.level < minLevel || !entryMatches(entry.line, where) {
+				return nil
+			}
+			var err error
+			if sse {
+				_, err = fmt.Fprintf(w, "data: %s\n\n", entry.line)
+			} else {
+				_, err = fmt.Fprintf(w, "%s\n", entry.line)
+			}
+			return err
+		}
+
+		if !follow {
+			for _, entry := range recentEntries.snapshot() {
+				if err := write(entry); err != nil {
+					return
+				}
+			}
+			return
+		}
+		kept, ch, cancel := recentEntries.subscribe()
+		defer cancel()
+		for _, entry := range kept {
+			if err := write(entry); err != nil {
+				return
+			}
+		}
+		for {
+			_ = controller.Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case entry := <-ch:
+				if err := write(entry); err != nil {
+					return
+				}
+			}
+		}
+	})
+}
+
+// entryMatches reports whether every field of the JSON encoded entry equals its value in where
+func entryMatches(line []byte, where map[string]string) bool {
+	if len(where) == 0 {
+		return true
+	}
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return false
+	}
+	for key, value := range where {
+		field, ok := fields[key]
+		if !ok || fmt.Sprint(field) != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// withRecentEntries keeps the last size entries for the test, disabling them after it
+func withRecentEntries(t *testing.T, size int) {
+	t.Helper()
+	recentEntries.resize(size)
+	t.Cleanup(func() { recentEntries.resize(0) })
+}
+
+func addRecentEntry(level zapcore.Level, message, component string) {
+	line := fmt.Sprintf(`{"level":%q,"msg":%q,"component":%q}`, level.String(), message, component)
+	recentEntries.add(recentEntry{level: level, line: []byte(line)})
+}
+
+func TestEntryRingKeepsLastEntries(t *testing.T) {
+	withRecentEntries(t, 3)
+	for i := 1; i <= 5; i++ {
+		addRecentEntry(zapcore.InfoLevel, fmt.Sprint(i), "")
+	}
+	kept := recentEntries.snapshot()
+	if len(kept) != 3 {
+		t.Fatalf("kept %d entries, want 3", len(kept))
+	}
+	for i, entry := range kept {
+		if want := fmt.Sprintf(`"msg":"%d"`, i+3); !strings.Contains(string(entry.line), want) {
+			t.Errorf("entry %d = %s, want %s", i, entry.line, want)
+		}
+	}
+}
+
+func TestDebugLogsHandlerFilters(t *testing.T) {
+	withRecentEntries(t, 10)
+	addRecentEntry(zapcore.InfoLevel, "info", "payments")
+	addRecentEntry(zapcore.WarnLevel, "warn", "payments")
+	addRecentEntry(zapcore.ErrorLevel, "error", "orders")
+
+	rec := httptest.NewRecorder()
+	DebugLogsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs?follow=false&level=warn&component=payments", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("got %d %s, want 200 application/x-ndjson", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if want := `{"level":"warn","msg":"warn","component":"payments"}` + "\n"; rec.Body.String() != want {
+		t.Errorf("body = %q, want %q", rec.Body.String(), want)
+	}
+
+	rec = httptest.NewRecorder()
+	DebugLogsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs?level=loud", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("got %d for an invalid level, want 400", rec.Code)
+	}
+}
+
+func TestDebugLogsHandlerDisabled(t *testing.T) {
+	withRecentEntries(t, 0)
+	rec := httptest.NewRecorder()
+	DebugLogsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got %d, want 404", rec.Code)
+	}
+}
+
+func TestDebugLogsHandlerStreamsSSE(t *testing.T) {
+	withRecentEntries(t, 10)
+	addRecentEntry(zapcore.InfoLevel, "kept", "")
+
+	// The access log middleware wraps the response writer, the live tail must still be flushed
+	server := httptest.NewServer(HTTPMiddleware(DebugLogsHandler()))
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %s, want text/event-stream", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		t.Helper()
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := reader.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		}
+		return line
+	}
+	if event := readEvent(); !strings.HasPrefix(event, "data: ") || !strings.Contains(event, `"msg":"kept"`) {
+		t.Errorf("first event = %q, want the kept entry", event)
+	}
+	// The kept entries are written once subscribed, so the new entry is part of the live tail
+	addRecentEntry(zapcore.WarnLevel, "live", "")
+	if event := readEvent(); !strings.Contains(event, `"msg":"live"`) {
+		t.Errorf("second event = %q, want the live entry", event)
+	}
+}
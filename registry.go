@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// registry caches the named loggers by name
+var registry sync.Map
+
+// namedLogger is a component logger following the global logger across re-initializations
+type namedLogger struct {
+	name    string
+	derived atomic.Pointer[derivedLogger]
+}
+
+// derivedLogger is the component logger derived from one build of the global logger
+type derivedLogger struct {
+	build  *zapBuild
+	logger *zapLogger
+}
+
+// Get returns the logger of the named component, created on first use and cached for the process.
+// Its entries carry the component field, which filters such as ComponentEquals can match on.
+func Get(name string) ILogger {
+	if l, ok := registry.Load(name); ok {
+		return l.(*namedLogger)
+	}
+	l, _ := registry.LoadOrStore(name, &namedLogger{name: name})
+	return l.(*namedLogger)
+}
+
+// current returns the component logger derived from the current global logger
+func (n *namedLogger) current() *zapLogger {
+	z := currentLogger()
+	if z == nil {
+		return nopLogger
+	}
+	if derived := n.derived.Load(); derived != nil && derived.build == z.build {
+		return derived.logger
+	}
+	// Skip the frame of the namedLogger method so callers are reported correctly
+	fields := append(append([]interface{}(nil), z.fields...), ComponentKey, n.name)
+	logger := &zapLogger{sugar: z.sugar.WithOptions(zap.AddCallerSkip(1)), fields: fields, build: z.build}
+	n.derived.Store(&derivedLogger{build: z.build, logger: logger})
+	return logger
+}
+
+func (n *namedLogger) Write(p []byte) (int, error) {
+	return n.current().Write(p)
+}
+
+func (n *namedLogger) Debug(message string, fields ...interface{}) {
+	n.current().Debug(message, fields...)
+}
+
+func (n *namedLogger) Infof(message string, fields ...interface{}) {
+	n.current().Infof(message, fields...)
+}
+
+func (n *namedLogger) Info(message string, fields ...interface{}) {
+	n.current().Info(message, fields...)
+}
+
+func (n *namedLogger) Warn(message string, fields ...interface{}) {
+	n.current().Warn(message, fields...)
+}
+
+func (n *namedLogger) Error(message string, fields ...interface{}) {
+	n.current().Error(message, fields...)
+}
+
+func (n *namedLogger) Fatal(message string, fields ...interface{}) {
+	n.current().Fatal(message, fields...)
+}
+
+func (n *namedLogger) log(level zapcore.Level, message string, fields []interface{}) {
+	n.current().log(level, message, fields)
+}
+
+func (n *namedLogger) enabled(level zapcore.Level) bool {
+	return n.current().enabled(level)
+}
@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// RemoteSettings are the logger settings managed by a remote config source, unset values keep
+// the current setting
+type RemoteSettings struct {
+	LogMode            string `json:"log_mode,omitempty"`
+	SamplingEnabled    *bool  `json:"sampling_enabled,omitempty"`
+	SamplingInitial    int    `json:"sampling_initial,omitempty"`
+	SamplingThereafter int    `json:"sampling_thereafter,omitempty"`
+}
+
+// RemoteConfigSource fetches the logger settings from a remote config store
+type RemoteConfigSource interface {
+	Fetch(ctx context.Context) (RemoteSettings, error)
+}
+
+// HTTPConfigSource fetches the settings as JSON from an HTTP endpoint. A Consul key can be read
+// through its KV API with the raw parameter, e.g. http://consul:8500/v1/kv/logger/payments?raw.
+type HTTPConfigSource struct {
+	URL    string
+	Header http.Header  // optional headers, e.g. an auth token
+	Client *http.Client // optional, defaults to a client with a 10 second timeout
+}
+
+var defaultRemoteConfigClient = &http.Client{Timeout: 10 * time.Second}
+
+// Fetch fetches the settings from the URL
+func (h *HTTPConfigSource) Fetch(ctx context.Context) (RemoteSettings, error) {
+	var settings RemoteSettings
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return settings, err
+	}
+	for key, values := range h.Header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	client := h.Client
+	if client == nil {
+		client = defaultRemoteConfigClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return settings, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return settings, fmt.Errorf("unexpected status %s fetching logger settings", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return settings, err
+	}
+	err = json.Unmarshal(body, &settings)
+	return settings, err
+}
+
+// StartRemoteConfigPoller fetches the settings from the source at every interval and applies
+// them to the running logger until the context is done, the interval must be positive
+func StartRemoteConfigPoller(ctx context.Context, source RemoteConfigSource, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid remote config poll interval %s: must be positive", interval)
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			pollRemoteConfig(ctx, source)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// pollRemoteConfig applies the fetched settings, failures are reported on std err to keep the
+// console log stream JSON
+func pollRemoteConfig(ctx context.Context, source RemoteConfigSource) {
+	settings, err := source.Fetch(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to fetch remote logger settings", err.Error())
+		return
+	}
+	if err := applyRemoteSettings(settings); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to apply remote logger settings", err.Error())
+	}
+}
+
+func applyRemoteSettings(settings RemoteSettings) error {
+	if settings.LogMode != "" {
+		if err := SetLogMode(settings.LogMode); err != nil {
+			return err
+		}
+	}
+	if settings.SamplingEnabled != nil {
+		initial := firstPositive(settings.SamplingInitial, int(sampling.initial.Load()), defaultSamplingInitial)
+		thereafter := firstPositive(settings.SamplingThereafter, int(sampling.thereafter.Load()), defaultSamplingThereafter)
+		SetSampling(*settings.SamplingEnabled, initial, thereafter)
+	}
+	return nil
+}
+
+func firstPositive(values ...int) int {
+	for _, value := range values {
+		if value > 0 {
+			return value
+		}
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeConfigSource returns its settings or error on every fetch
+type fakeConfigSource struct {
+	settings RemoteSettings
+	err      error
+}
+
+func (f fakeConfigSource) Fetch(context.Context) (RemoteSettings, error) {
+	return f.settings, f.err
+}
+
+func initRemoteTestLogger(t *testing.T) {
+	t.Helper()
+	config := NewDefaultLoggerConfig()
+	config.ConsoleSyncerDisabled = true
+	config.FileSyncerPath = filepath.Join(t.TempDir(), "app.log")
+	if err := Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitWithConfig(ZapLogger, config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reset() })
+}
+
+func TestStartRemoteConfigPollerRejectsInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := StartRemoteConfigPoller(context.Background(), fakeConfigSource{}, interval); err == nil {
+			t.Errorf("StartRemoteConfigPoller with interval %s succeeded, want an error", interval)
+		}
+	}
+}
+
+func TestStartRemoteConfigPollerAppliesSettings(t *testing.T) {
+	initRemoteTestLogger(t)
+	withSampling(t, false, defaultSamplingInitial, defaultSamplingThereafter)
+	enabled := true
+	source := fakeConfigSource{settings: RemoteSettings{LogMode: "debug", SamplingEnabled: &enabled, SamplingInitial: 5}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := StartRemoteConfigPoller(ctx, source, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// The settings are fetched once right away, not after the first interval
+	deadline := time.Now().Add(5 * time.Second)
+	for currentLogger().build.level.Level() != zapcore.DebugLevel {
+		if time.Now().After(deadline) {
+			t.Fatal("the remote log mode was not applied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for !sampling.enabled.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("the remote sampling was not applied")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if initial, thereafter := sampling.initial.Load(), sampling.thereafter.Load(); initial != 5 || thereafter != defaultSamplingThereafter {
+		t.Errorf("sampling initial %d and thereafter %d, want 5 and the current %d", initial, thereafter, defaultSamplingThereafter)
+	}
+}
+
+func TestApplyRemoteSettings(t *testing.T) {
+	initRemoteTestLogger(t)
+	withSampling(t, false, 10, 20)
+	enabled := true
+
+	if err := applyRemoteSettings(RemoteSettings{LogMode: "LOUD", SamplingEnabled: &enabled}); err == nil {
+		t.Error("an invalid remote log mode was applied")
+	}
+	if sampling.enabled.Load() {
+		t.Error("sampling was applied along with an invalid log mode")
+	}
+
+	// Unset values keep the current settings
+	if err := applyRemoteSettings(RemoteSettings{}); err != nil {
+		t.Fatal(err)
+	}
+	if level := currentLogger().build.level.Level(); level != zapcore.InfoLevel || sampling.enabled.Load() {
+		t.Errorf("empty settings changed the level to %v or enabled sampling", level)
+	}
+	if err := applyRemoteSettings(RemoteSettings{LogMode: "WARN", SamplingEnabled: &enabled, SamplingThereafter: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if level := currentLogger().build.level.Level(); level != zapcore.WarnLevel {
+		t.Errorf("level = %v, want warn", level)
+	}
+	if !sampling.enabled.Load() || sampling.initial.Load() != 10 || sampling.thereafter.Load() != 7 {
+		t.Errorf("sampling %v %d %d, want enabled 10 7", sampling.enabled.Load(), sampling.initial.Load(), sampling.thereafter.Load())
+	}
+}
+
+func TestPollRemoteConfigReportsErrorsOnStderr(t *testing.T) {
+	stdout, stderr := os.Stdout, os.Stderr
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outW, errW
+	pollRemoteConfig(context.Background(), fakeConfigSource{err: errors.New("connection refused")})
+	pollRemoteConfig(context.Background(), fakeConfigSource{settings: RemoteSettings{LogMode: "LOUD"}})
+	os.Stdout, os.Stderr = stdout, stderr
+	outW.Close()
+	errW.Close()
+	printedOut, _ := io.ReadAll(outR)
+	printedErr, _ := io.ReadAll(errR)
+
+	if len(printedOut) != 0 {
+		t.Errorf("std out = %q, want nothing in the log stream", printedOut)
+	}
+	if !strings.Contains(string(printedErr), "failed to fetch remote logger settings connection refused") ||
+		!strings.Contains(string(printedErr), "failed to apply remote logger settings") {
+		t.Errorf("std err = %q, want both failures", printedErr)
+	}
+}
+
+func TestHTTPConfigSourceFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		_, _ = io.WriteString(w, `{"log_mode": "DEBUG", "sampling_enabled": false}`)
+	}))
+	defer server.Close()
+
+	source := &HTTPConfigSource{URL: server.URL, Header: http.Header{"X-Consul-Token": []string{"secret"}}}
+	settings, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.LogMode != "DEBUG" || settings.SamplingEnabled == nil || *settings.SamplingEnabled {
+		t.Errorf("settings = %+v, want DEBUG with sampling disabled", settings)
+	}
+
+	source.Header = nil
+	if _, err := source.Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Fetch without the token = %v, want the unexpected status", err)
+	}
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	// closers release the files, connections and signal handlers opened by the initialized logger
+	closers   []func() error
+	closersMu sync.Mutex
+)
+
+func registerCloser(closer func() error) {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+	closers = append(closers, closer)
+}
+
+func closeSinks() error {
+	closersMu.Lock()
+	defer closersMu.Unlock()
+	var errs []error
+	for _, closer := range closers {
+		if err := closer(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	closers = nil
+	return errors.Join(errs...)
+}
+
+// Reset flushes and closes the sinks of the logger and allows InitWithConfig to initialize it
+// afresh, so test suites can switch configs between cases. Registered hooks, filters, transforms
+// and event types are kept.
+//
+// Reset is not safe to call while other goroutines log or initialize the logger: tests calling
+// it must not run in parallel (no t.Parallel) with each other or with tests that log.
+func Reset() error {
+	initMu.Lock()
+	defer initMu.Unlock()
+	if z := currentLogger(); z != nil {
+		_ = z.sugar.Sync()
+	}
+	swapLogger(nil)
+	auditLogger.Store(nil)
+	configuredSinks.Store(nil)
+	err := closeSinks()
+	once = sync.Once{}
+	return err
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// samplerCounters is the number of counters per level, entries are assigned by message hash
+	samplerCounters = 4096
+
+	defaultSamplingInitial    = 100
+	defaultSamplingThereafter = 100
+)
+
+// samplingSettings are read on every entry so sampling can be changed while logging
+type samplingSettings struct {
+	enabled    atomic.Bool
+	initial    atomic.Uint64
+	thereafter atomic.Uint64
+}
+
+var sampling samplingSettings
+
+// SetSampling changes the sampling of the running logger: per second the first initial entries
+// with the same level and message are logged and thereafter every Nth one
+func SetSampling(enabled bool, initial, thereafter int) {
+	if initial < 0 {
+		initial = 0
+	}
+	if thereafter < 0 {
+		thereafter = 0
+	}
+	sampling.initial.Store(uint64(initial))
+	sampling.thereafter.Store(uint64(thereafter))
+	sampling.enabled.Store(enabled)
+}
+
+type samplerCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+// incCheckReset increments the counter, restarting it once the tick has passed
+func (c *samplerCounter) incCheckReset(t time.Time, tick time.Duration) uint64 {
+	tn := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > tn {
+		return c.count.Add(1)
+	}
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, tn+tick.Nanoseconds()) {
+		// Another goroutine restarted the counter first
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// samplerCounts are indexed by level - TraceLevel, every level below DEBUG shares the trace counters
+type samplerCounts [zapcore.FatalLevel - TraceLevel + 1][samplerCounters]samplerCounter
+
+// lazySamplerCounts allocates the counters on the first sampled entry, they are large and most
+// cores, e.g. of tenants, never sample
+type lazySamplerCounts struct {
+	once   sync.Once
+	counts *samplerCounts
+}
+
+func (l *lazySamplerCounts) get() *samplerCounts {
+	l.once.Do(func() {
+		l.counts = &samplerCounts{}
+	})
+	return l.counts
+}
+
+// samplerCore samples entries like zap's sampler, but following the current sampling settings
+type samplerCore struct {
+	zapcore.Core
+	counts *lazySamplerCounts
+}
+
+// newSampledCore wraps the core with a sampler following the current sampling settings
+func newSampledCore(core zapcore.Core) zapcore.Core {
+	return &samplerCore{Core: core, counts: &lazySamplerCounts{}}
+}
+
+// Level returns the minimum enabled level of the wrapped core, including levels below DEBUG
+func (s *samplerCore) Level() zapcore.Level {
+	return zapcore.LevelOf(s.Core)
+}
+
+func (s *samplerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplerCore{Core: s.Core.With(fields), counts: s.counts}
+}
+
+func (s *samplerCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !s.Enabled(entry.Level) {
+		return ce
+	}
+	if sampling.enabled.Load() && entry.Level <= zapcore.FatalLevel {
+		level := entry.Level
+		if level < TraceLevel {
+			level = TraceLevel
+		}
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(entry.Message))
+		counter := &s.counts.get()[level-TraceLevel][h.Sum32()%samplerCounters]
+		n := counter.incCheckReset(entry.Time, time.Second)
+		initial, thereafter := sampling.initial.Load(), sampling.thereafter.Load()
+		if n > initial && (thereafter == 0 || (n-initial)%thereafter != 0) {
+			reportDropped(entry, DropReasonSampled)
+			return ce
+		}
+	}
+	return s.Core.Check(entry, ce)
+}
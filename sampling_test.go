@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// withSampling sets the sampling settings for the test, restoring the current ones after it
+func withSampling(t *testing.T, enabled bool, initial, thereafter int) {
+	t.Helper()
+	wasEnabled, wasInitial, wasThereafter := sampling.enabled.Load(), sampling.initial.Load(), sampling.thereafter.Load()
+	SetSampling(enabled, initial, thereafter)
+	t.Cleanup(func() { SetSampling(wasEnabled, int(wasInitial), int(wasThereafter)) })
+}
+
+// writeEntry checks the entry against the core and writes it if sampled in
+func writeEntry(core zapcore.Core, level zapcore.Level, message string, at time.Time) {
+	if ce := core.Check(zapcore.Entry{Level: level, Message: message, Time: at}, nil); ce != nil {
+		ce.Write()
+	}
+}
+
+func TestSamplerCoreLogsInitialThenEveryNth(t *testing.T) {
+	withSampling(t, true, 2, 3)
+	observed, logs := observer.New(TraceLevel)
+	core := newSampledCore(observed)
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		writeEntry(core, zapcore.InfoLevel, "repeated", now)
+	}
+	// The 1st and 2nd entries are the initial ones, then every 3rd: the 5th and the 8th
+	if got := logs.FilterMessage("repeated").Len(); got != 4 {
+		t.Errorf("logged %d of 10 entries, want 4", got)
+	}
+
+	// Counters are per level and message, and restart after a second
+	writeEntry(core, zapcore.WarnLevel, "repeated", now)
+	writeEntry(core, zapcore.InfoLevel, "other", now)
+	writeEntry(core, zapcore.InfoLevel, "repeated", now.Add(time.Second))
+	if got := logs.Len(); got != 7 {
+		t.Errorf("logged %d entries, want 7", got)
+	}
+}
+
+func TestSamplerCoreFollowsSettings(t *testing.T) {
+	withSampling(t, false, 1, 0)
+	observed, logs := observer.New(TraceLevel)
+	core := newSampledCore(observed)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		writeEntry(core, TraceLevel, "trace", now)
+	}
+	if logs.Len() != 3 {
+		t.Errorf("logged %d of 3 entries with sampling disabled, want 3", logs.Len())
+	}
+	if core.(*samplerCore).counts.counts != nil {
+		t.Error("sampler counters were allocated with sampling disabled")
+	}
+
+	// With thereafter 0 only the initial entries are logged
+	SetSampling(true, 1, 0)
+	for i := 0; i < 3; i++ {
+		writeEntry(core, TraceLevel, "sampled", now)
+	}
+	if got := logs.FilterMessage("sampled").Len(); got != 1 {
+		t.Errorf("logged %d of 3 entries, want 1", got)
+	}
+}
+
+func TestSamplerCoreReportsLevelBelowDebug(t *testing.T) {
+	core := newIOCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zap.NewAtomicLevelAt(TraceLevel))
+	if level := zapcore.LevelOf(newSampledCore(core)); level != TraceLevel {
+		t.Errorf("level = %v, want %v", level, TraceLevel)
+	}
+}
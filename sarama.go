@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SaramaLogger adapts an ILogger to the sarama.Logger and sarama.StdLogger interfaces, so Kafka
+// client internals such as rebalances and broker errors are logged with component=kafka-client.
+// Messages mentioning an error or failure are logged at WARN, the others at the logger's level.
+type SaramaLogger struct {
+	logger ILogger
+	level  zapcore.Level
+}
+
+// NewSaramaLogger creates a sarama logger writing at INFO through the logger, nil uses the
+// global logger. Assign it to sarama.Logger.
+func NewSaramaLogger(logger ILogger) *SaramaLogger {
+	return &SaramaLogger{logger: logger, level: zapcore.InfoLevel}
+}
+
+// NewSaramaDebugLogger creates a sarama logger writing at DEBUG through the logger, nil uses the
+// global logger. Assign it to sarama.DebugLogger.
+func NewSaramaDebugLogger(logger ILogger) *SaramaLogger {
+	return &SaramaLogger{logger: logger, level: zapcore.DebugLevel}
+}
+
+// Print implements sarama.StdLogger
+func (s *SaramaLogger) Print(v ...interface{}) {
+	s.log(fmt.Sprint(v...))
+}
+
+// Printf implements sarama.StdLogger
+func (s *SaramaLogger) Printf(format string, v ...interface{}) {
+	s.log(fmt.Sprintf(format, v...))
+}
+
+// Println implements sarama.StdLogger
+func (s *SaramaLogger) Println(v ...interface{}) {
+	s.log(fmt.Sprintln(v...))
+}
+
+func (s *SaramaLogger) log(message string) {
+	message = strings.TrimSpace(message)
+	level := s.level
+	lowerMessage := strings.ToLower(message)
+	if strings.Contains(lowerMessage, "error") || strings.Contains(lowerMessage, "fail") {
+		level = max(level, zapcore.WarnLevel)
+	}
+
+	logger := s.logger
+	if logger == nil {
+		logger = L()
+	}
+	LogAt(logger, level, message, ComponentKey, "kafka-client")
+}
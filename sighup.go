@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// reopenOnSIGHUP closes the log file whenever the process receives SIGHUP. lumberjack reopens
+// the configured path on the next write, so files moved away by an external logrotate are
+// released and a fresh file is created in their place. The returned function stops it.
+func reopenOnSIGHUP(lumberjackLogger *lumberjack.Logger) (stop func() error) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			if err := lumberjackLogger.Close(); err != nil {
+				fmt.Println("failed to reopen log file on SIGHUP", err.Error())
+			}
+		}
+	}()
+	return func() error {
+		signal.Stop(hupCh)
+		close(hupCh)
+		return nil
+	}
+}
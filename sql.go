@@ -0,0 +1,305 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SQLLoggerConfig is the config for the database/sql query logging wrapper
+type SQLLoggerConfig struct {
+	LogMode             string                                  // DEBUG, INFO, WARN, level of the query logs, failed queries are logged at ERROR (default: DEBUG)
+	SlowQueryThreshold  time.Duration                           // to log queries taking longer at WARN, 0 disables it (default: 0)
+	ArgsLoggingDisabled bool                                    // to not log the query args at all (default: false)
+	RedactArg           func(arg driver.NamedValue) interface{} // to set how an arg is logged (default: RedactArgType, logs its type only)
+}
+
+// NewDefaultSQLLoggerConfig creates a new default query logging config
+func NewDefaultSQLLoggerConfig() *SQLLoggerConfig {
+	return &SQLLoggerConfig{
+		LogMode:             "DEBUG",
+		SlowQueryThreshold:  0,
+		ArgsLoggingDisabled: false,
+		RedactArg:           RedactArgType,
+	}
+}
+
+// RedactArgType logs the type of the arg in place of its value
+func RedactArgType(arg driver.NamedValue) interface{} {
+	if arg.Value == nil {
+		return nil
+	}
+	return fmt.Sprintf("%T", arg.Value)
+}
+
+// WrapConnector wraps the connector so every query run through it is logged with its args,
+// duration and error, use it with sql.OpenDB. A nil config uses the default one.
+func WrapConnector(connector driver.Connector, config *SQLLoggerConfig) driver.Connector {
+	config = sqlLoggerConfigOrDefault(config)
+	return &loggingConnector{connector: connector, driver: &loggingDriver{Driver: connector.Driver(), config: config}, config: config}
+}
+
+// WrapDriver wraps the driver so every query run through it is logged with its args, duration
+// and error, register it with sql.Register under a new name. A nil config uses the default one.
+func WrapDriver(d driver.Driver, config *SQLLoggerConfig) driver.Driver {
+	return &loggingDriver{Driver: d, config: sqlLoggerConfigOrDefault(config)}
+}
+
+func sqlLoggerConfigOrDefault(config *SQLLoggerConfig) *SQLLoggerConfig {
+	if config == nil {
+		return NewDefaultSQLLoggerConfig()
+	}
+	if config.RedactArg == nil {
+		c := *config
+		c.RedactArg = RedactArgType
+		return &c
+	}
+	return config
+}
+
+// logQuery logs a query, failed queries at ERROR and slow ones at WARN
+func (c *SQLLoggerConfig) logQuery(ctx context.Context, query string, args []driver.NamedValue, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	duration := time.Since(start)
+	fields := []interface{}{"query", query, "duration_ms", float64(duration) / float64(time.Millisecond)}
+	if !c.ArgsLoggingDisabled && len(args) > 0 {
+		loggedArgs := make([]interface{}, len(args))
+		for i, arg := range args {
+			loggedArgs[i] = c.RedactArg(arg)
+		}
+		fields = append(fields, "args", loggedArgs)
+	}
+
+	level := parseLogMode(c.LogMode)
+	if c.LogMode == "" {
+		level = zapcore.DebugLevel
+	}
+	switch {
+	case err != nil:
+		level = zapcore.ErrorLevel
+		fields = append(fields, "err", err.Error())
+	case c.SlowQueryThreshold > 0 && duration > c.SlowQueryThreshold:
+		level = zapcore.WarnLevel
+	}
+	LogAt(WithContext(ctx), level, "sql query", fields...)
+}
+
+type loggingConnector struct {
+	connector driver.Connector
+	driver    *loggingDriver
+	config    *SQLLoggerConfig
+}
+
+func (c *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{Conn: conn, config: c.config}, nil
+}
+
+func (c *loggingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type loggingDriver struct {
+	driver.Driver
+	config *SQLLoggerConfig
+}
+
+func (d *loggingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{Conn: conn, config: d.config}, nil
+}
+
+func (d *loggingDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.Driver.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &loggingConnector{connector: connector, driver: d, config: d.config}, nil
+	}
+	return &dsnConnector{name: name, driver: d}, nil
+}
+
+// dsnConnector connects through Open for drivers not implementing driver.DriverContext
+type dsnConnector struct {
+	name   string
+	driver *loggingDriver
+}
+
+func (c *dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type loggingConn struct {
+	driver.Conn
+	config *SQLLoggerConfig
+}
+
+func (c *loggingConn) Prepare(query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingStmt{Stmt: stmt, query: query, config: c.config}, nil
+}
+
+func (c *loggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if pc, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &loggingStmt{Stmt: stmt, query: query, config: c.config}, nil
+}
+
+// BeginTx falls back to Begin like database/sql does, refusing the options Begin cannot honor
+func (c *loggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bc, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return bc.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+	tx, err := c.Conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		_ = tx.Rollback()
+		return nil, ctx.Err()
+	default:
+	}
+	return tx, nil
+}
+
+func (c *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := ec.ExecContext(ctx, query, args)
+	c.config.logQuery(ctx, query, args, start, err)
+	return result, err
+}
+
+func (c *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	c.config.logQuery(ctx, query, args, start, err)
+	return rows, err
+}
+
+func (c *loggingConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) ResetSession(ctx context.Context) error {
+	if sr, ok := c.Conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *loggingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type loggingStmt struct {
+	driver.Stmt
+	query  string
+	config *SQLLoggerConfig
+}
+
+func (s *loggingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if ec, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = ec.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	s.config.logQuery(ctx, s.query, args, start, err)
+	return result, err
+}
+
+func (s *loggingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.config.logQuery(ctx, s.query, args, start, err)
+	return rows, err
+}
+
+func (s *loggingStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if nvc, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return nvc.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
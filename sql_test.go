@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeDriver is a database/sql driver whose conns implement ExecerContext only when execer is set
+type fakeDriver struct {
+	execer bool
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	if d.execer {
+		return &fakeExecerConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+// fakeConnector connects to the fake driver, for WrapConnector
+type fakeConnector struct {
+	driver fakeDriver
+}
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open("")
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+var errFakeQuery = errors.New("syntax error")
+
+// fakeConn only implements the mandatory driver.Conn methods, so database/sql falls back to
+// prepared statements
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{query: query}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+type fakeExecerConn struct {
+	fakeConn
+}
+
+func (c *fakeExecerConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "fail" {
+		return nil, errFakeQuery
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type fakeStmt struct {
+	query string
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.query == "fail" {
+		return nil, errFakeQuery
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return fakeRows{}, nil
+}
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string {
+	return []string{"n"}
+}
+
+func (fakeRows) Close() error {
+	return nil
+}
+
+func (fakeRows) Next([]driver.Value) error {
+	return io.EOF
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+// captureSQLEntries initializes the logger at DEBUG and returns a function listing the sql query
+// entries logged since
+func captureSQLEntries(t *testing.T) func() []Entry {
+	t.Helper()
+	return captureEntries(t, "sql query")
+}
+
+func field(entry Entry, key string) interface{} {
+	for i := 1; i < len(entry.Fields); i += 2 {
+		if entry.Fields[i-1] == key {
+			return entry.Fields[i]
+		}
+	}
+	return nil
+}
+
+func TestWrapDriverFallsBackToPreparedStatements(t *testing.T) {
+	entries := captureSQLEntries(t)
+	sql.Register("logger-fake-prepared", WrapDriver(fakeDriver{}, nil))
+	db, err := sql.Open("logger-fake-prepared", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// The conn returns driver.ErrSkip for Exec, database/sql prepares the statement instead
+	if _, err := db.Exec("insert", 42, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1 without the skipped exec: %v", len(got), got)
+	}
+	if query := field(got[0], "query"); query != "insert" {
+		t.Errorf("query = %v, want insert", query)
+	}
+	args, _ := field(got[0], "args").([]interface{})
+	if len(args) != 2 || args[0] != "int64" || args[1] != "string" {
+		t.Errorf("args = %v, want the redacted types [int64 string]", args)
+	}
+}
+
+func TestWrapDriverLogsPreparedStatements(t *testing.T) {
+	entries := captureSQLEntries(t)
+	sql.Register("logger-fake-stmt", WrapDriver(fakeDriver{}, nil))
+	db, err := sql.Open("logger-fake-stmt", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, err := db.Prepare("select")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	for i := 0; i < 2; i++ {
+		rows, err := stmt.Query(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.Close()
+	}
+	got := entries()
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2: %v", len(got), got)
+	}
+	for _, entry := range got {
+		if query := field(entry, "query"); query != "select" || entry.Level != zapcore.DebugLevel {
+			t.Errorf("got %v at %v, want select at DEBUG", query, entry.Level)
+		}
+	}
+}
+
+func TestWrapConnectorLogsFailedQueriesAtError(t *testing.T) {
+	entries := captureSQLEntries(t)
+	config := NewDefaultSQLLoggerConfig()
+	config.RedactArg = func(arg driver.NamedValue) interface{} { return "***" }
+	db := sql.OpenDB(WrapConnector(fakeConnector{driver: fakeDriver{execer: true}}, config))
+	defer db.Close()
+
+	if _, err := db.Exec("fail", "secret"); !errors.Is(err, errFakeQuery) {
+		t.Fatalf("err = %v, want %v", err, errFakeQuery)
+	}
+	got := entries()
+	if len(got) != 1 {
+		t.Fatalf("got %d entries, want 1: %v", len(got), got)
+	}
+	if got[0].Level != zapcore.ErrorLevel || field(got[0], "err") != errFakeQuery.Error() {
+		t.Errorf("got %v with err %v, want ERROR with %q", got[0].Level, field(got[0], "err"), errFakeQuery)
+	}
+	if args, _ := field(got[0], "args").([]interface{}); len(args) != 1 || args[0] != "***" {
+		t.Errorf("args = %v, want [***]", args)
+	}
+}
+
+func TestWrapConnectorBeginTxRefusesUnsupportedOptions(t *testing.T) {
+	captureSQLEntries(t)
+	db := sql.OpenDB(WrapConnector(fakeConnector{driver: fakeDriver{}}, nil))
+	defer db.Close()
+	ctx := context.Background()
+
+	if _, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}); err == nil {
+		t.Error("BeginTx with a non-default isolation level succeeded on a driver without ConnBeginTx")
+	}
+	if _, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true}); err == nil {
+		t.Error("BeginTx read-only succeeded on a driver without ConnBeginTx")
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"expvar"
+
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerStats is a snapshot of the logger's internal counters since the process started
+type LoggerStats struct {
+	Entries      map[string]uint64 `json:"entries"`       // entries written per level
+	BytesWritten map[string]uint64 `json:"bytes_written"` // bytes written per sink
+	WriteErrors  map[string]uint64 `json:"write_errors"`  // failed writes per sink
+}
+
+// Stats returns a snapshot of the logger's internal counters
+func Stats() LoggerStats {
+	stats := LoggerStats{
+		Entries:      make(map[string]uint64, len(entryCounts)),
+		BytesWritten: make(map[string]uint64, len(sinkNames)),
+		WriteErrors:  make(map[string]uint64, len(sinkNames)),
+	}
+	for i := range entryCounts {
+		level := TraceLevel + zapcore.Level(i)
+		stats.Entries[logformat.LevelName(level)] = entryCounts[i].Load()
+	}
+	for _, sink := range sinkNames {
+		stats.BytesWritten[sink] = sinkBytesCounts[sink].Load()
+		stats.WriteErrors[sink] = sinkErrorCounts[sink].Load()
+	}
+	return stats
+}
+
+// PublishExpvar publishes the logger stats under the given expvar name, e.g. to be served on
+// /debug/vars. Like expvar.Publish it panics if the name is already in use.
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return Stats()
+	}))
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"log"
+	"strings"
+)
+
+// serverNoise are fragments of http.Server error logs caused by misbehaving clients rather than the
+// server, they are logged at WARN
+var serverNoise = []string{
+	"TLS handshake error",
+	"client sent an HTTP request to an HTTPS server",
+}
+
+// NewServerErrorLog creates a *log.Logger for http.Server.ErrorLog and similar stdlib hooks
+// writing through the logger, nil uses the global logger. TLS handshake errors and other client
+// noise are logged at WARN, everything else at ERROR.
+func NewServerErrorLog(logger ILogger) *log.Logger {
+	return log.New(&serverErrorWriter{logger: logger}, "", 0)
+}
+
+type serverErrorWriter struct {
+	logger ILogger
+}
+
+func (w *serverErrorWriter) Write(p []byte) (int, error) {
+	message := strings.TrimSpace(string(p))
+	logger := w.logger
+	if logger == nil {
+		logger = L()
+	}
+	for _, noise := range serverNoise {
+		if strings.Contains(message, noise) {
+			logger.Warn(message, ComponentKey, "http-server")
+			return len(p), nil
+		}
+	}
+	logger.Error(message, ComponentKey, "http-server")
+	return len(p), nil
+}
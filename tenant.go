@@ -0,0 +1,202 @@
+package logger
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// tenantIDKey is the tenant field used to route the logs of a tenant to its own file
+const tenantIDKey = "tenant_id"
+
+// TenantResolver resolves the tenant fields, e.g. tenant_id and org_id, of a context
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context) map[string]interface{}
+}
+
+// TenantResolverFunc adapts a function to a TenantResolver
+type TenantResolverFunc func(ctx context.Context) map[string]interface{}
+
+// ResolveTenant calls f(ctx)
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context) map[string]interface{} {
+	return f(ctx)
+}
+
+var (
+	tenantResolver   TenantResolver
+	tenantResolverMu sync.RWMutex
+)
+
+// SetTenantResolver sets the resolver whose fields WithContext attaches to every entry
+func SetTenantResolver(resolver TenantResolver) {
+	tenantResolverMu.Lock()
+	defer tenantResolverMu.Unlock()
+	tenantResolver = resolver
+}
+
+// resolveTenantFields returns the tenant fields of the context as key value pairs sorted by key
+func resolveTenantFields(ctx context.Context) (fields []interface{}, tenantID string) {
+	tenantResolverMu.RLock()
+	resolver := tenantResolver
+	tenantResolverMu.RUnlock()
+	if resolver == nil || ctx == nil {
+		return nil, ""
+	}
+
+	tenantFields := resolver.ResolveTenant(ctx)
+	keys := make([]string, 0, len(tenantFields))
+	for key := range tenantFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, key, tenantFields[key])
+	}
+	if id, ok := tenantFields[tenantIDKey]; ok {
+		tenantID = fmt.Sprint(id)
+	}
+	return fields, tenantID
+}
+
+// tenantIDPattern are the tenant ids routed to their own file, the id names the file so it must not
+// be able to escape the tenant directory
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,127}$`)
+
+// tenantSink holds the file of a tenant and the loggers writing to it. The loggers write through
+// the cache, so the DEBUG variant appends to the same file and loggers still held after an
+// eviction write to the file of the cached sink instead of reopening the evicted one.
+type tenantSink struct {
+	tenantID    string
+	file        *lumberjack.Logger
+	logger      *zap.Logger
+	debugLogger *zap.Logger
+}
+
+func (t *tenantSink) pick(debug bool) *zap.Logger {
+	if debug {
+		return t.debugLogger
+	}
+	return t.logger
+}
+
+// tenantCache keeps the sinks of the most recently used tenants, the file of an evicted tenant is
+// closed so tenant ids coming from requests cannot exhaust the file descriptors
+type tenantCache struct {
+	mu      sync.Mutex
+	max     int
+	create  func(tenantID string) *tenantSink
+	order   *list.List // of *tenantSink, most recently used first
+	entries map[string]*list.Element
+	closed  bool
+}
+
+func newTenantCache(max int, create func(tenantID string) *tenantSink) *tenantCache {
+	return &tenantCache{max: max, create: create, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// get returns the sink of the tenant, created when it is not cached
+func (c *tenantCache) get(tenantID string) *tenantSink {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.getLocked(tenantID)
+}
+
+func (c *tenantCache) getLocked(tenantID string) *tenantSink {
+	if e, ok := c.entries[tenantID]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*tenantSink)
+	}
+	sink := c.create(tenantID)
+	c.entries[tenantID] = c.order.PushFront(sink)
+	for c.max > 0 && c.order.Len() > c.max {
+		oldest := c.order.Remove(c.order.Back()).(*tenantSink)
+		delete(c.entries, oldest.tenantID)
+		if err := oldest.file.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to close tenant log file", err.Error())
+		}
+	}
+	return sink
+}
+
+// write writes to the file of the tenant's cached sink. Holding the lock while writing keeps an
+// eviction from closing the file mid-write, a closed file would be reopened by lumberjack and
+// never closed again.
+func (c *tenantCache) write(tenantID string, p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return 0, errTenantSinksClosed
+	}
+	return c.getLocked(tenantID).file.Write(p)
+}
+
+var errTenantSinksClosed = errors.New("tenant log files are closed")
+
+func (c *tenantCache) closeAll() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		if err := e.Value.(*tenantSink).file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.order.Init()
+	c.entries = map[string]*list.Element{}
+	c.closed = true
+	return errors.Join(errs...)
+}
+
+// tenantWriteSyncer writes to the file of the tenant's current sink in the cache
+type tenantWriteSyncer struct {
+	cache    *tenantCache
+	tenantID string
+}
+
+func (t *tenantWriteSyncer) Write(p []byte) (int, error) {
+	return t.cache.write(t.tenantID, p)
+}
+
+func (t *tenantWriteSyncer) Sync() error {
+	return nil
+}
+
+// tenantLogger returns the logger writing to the tenant's own file when tenant routing is enabled,
+// debug enables the DEBUG level regardless of the configured one
+func (b *zapBuild) tenantLogger(tenantID string, debug bool) (*zap.Logger, bool) {
+	if b == nil || b.config == nil || !b.config.TenantRoutingEnabled || !tenantIDPattern.MatchString(tenantID) {
+		return nil, false
+	}
+	b.tenantsOnce.Do(func() {
+		b.tenants = newTenantCache(b.config.TenantMaxOpenFiles, b.newTenantSink)
+		registerCloser(b.tenants.closeAll)
+	})
+	return b.tenants.get(tenantID).pick(debug), true
+}
+
+// newTenantSink creates the sink of a tenant, its file is opened on the first write
+func (b *zapBuild) newTenantSink(tenantID string) *tenantSink {
+	writeSyncer := newCountingWriteSyncer(sinkFile, &tenantWriteSyncer{cache: b.tenants, tenantID: tenantID})
+	return &tenantSink{
+		tenantID: tenantID,
+		file: &lumberjack.Logger{
+			Filename:   filepath.Join(b.config.TenantFileDir, tenantID+".log"),
+			MaxSize:    b.config.FileSyncerMaxSize,
+			MaxBackups: b.config.FileSyncerMaxBackups,
+			MaxAge:     b.config.FileSyncerMaxAge,
+			Compress:   b.config.FileSyncerCompress,
+			LocalTime:  true,
+		},
+		logger:      zap.New(b.sampledCore(writeSyncer, b.level), b.options...),
+		debugLogger: zap.New(b.sampledCore(writeSyncer, debugEnabler{level: b.level}), b.options...),
+	}
+}
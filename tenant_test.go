@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+func TestTenantIDPattern(t *testing.T) {
+	for id, want := range map[string]bool{
+		"acme":                   true,
+		"tenant-42":              true,
+		"org_1.eu":               true,
+		"":                       false,
+		"../etc":                 false,
+		".hidden":                false,
+		"a/b":                    false,
+		`a\b`:                    false,
+		"a b":                    false,
+		strings.Repeat("a", 128): true,
+		strings.Repeat("a", 129): false,
+	} {
+		if got := tenantIDPattern.MatchString(id); got != want {
+			t.Errorf("tenantIDPattern.MatchString(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestTenantCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	var created []string
+	cache := newTenantCache(2, func(tenantID string) *tenantSink {
+		created = append(created, tenantID)
+		return &tenantSink{tenantID: tenantID, file: &lumberjack.Logger{Filename: filepath.Join(dir, tenantID+".log")}}
+	})
+	defer cache.closeAll()
+
+	a := cache.get("a")
+	cache.get("b")
+	if cache.get("a") != a {
+		t.Error("a cached tenant got a new sink")
+	}
+	cache.get("c")
+	if _, ok := cache.entries["b"]; ok {
+		t.Error("b is still cached, want it evicted as the least recently used")
+	}
+	if _, ok := cache.entries["a"]; !ok || cache.order.Len() != 2 {
+		t.Errorf("cached %d sinks without a, want a and c", cache.order.Len())
+	}
+	cache.get("b")
+	if want := []string{"a", "b", "c", "b"}; strings.Join(created, ",") != strings.Join(want, ",") {
+		t.Errorf("created sinks %v, want %v", created, want)
+	}
+}
+
+func TestTenantCacheRefusesWritesOnceClosed(t *testing.T) {
+	cache := newTenantCache(0, func(tenantID string) *tenantSink {
+		return &tenantSink{tenantID: tenantID, file: &lumberjack.Logger{Filename: filepath.Join(t.TempDir(), tenantID+".log")}}
+	})
+	if _, err := cache.write("a", []byte("entry\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.closeAll(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.write("a", []byte("entry\n")); err != errTenantSinksClosed {
+		t.Errorf("write after closeAll = %v, want %v", err, errTenantSinksClosed)
+	}
+}
+
+type tenantContextKey struct{}
+
+func TestTenantLoggerHeldAcrossEviction(t *testing.T) {
+	dir := t.TempDir()
+	config := NewDefaultLoggerConfig()
+	config.ConsoleSyncerDisabled = true
+	config.FileSyncerPath = filepath.Join(dir, "app.log")
+	config.TenantRoutingEnabled = true
+	config.TenantFileDir = dir
+	config.TenantMaxOpenFiles = 1
+	if err := Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err := InitWithConfig(ZapLogger, config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Reset() })
+	SetTenantResolver(TenantResolverFunc(func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{tenantIDKey: ctx.Value(tenantContextKey{})}
+	}))
+	t.Cleanup(func() { SetTenantResolver(nil) })
+	ctxA := context.WithValue(context.Background(), tenantContextKey{}, "a")
+	ctxB := context.WithValue(context.Background(), tenantContextKey{}, "b")
+
+	held := WithContext(ctxA)
+	held.Info("first")
+	WithContext(ctxB).Info("evicts a")
+	// The held logger of the evicted tenant writes through the cache instead of reopening its file
+	held.Info("second")
+	WithContext(ContextWithDebug(ctxA)).Debug("third")
+	WithContext(ctxA).Info("fourth")
+
+	if n := currentLogger().build.tenants.order.Len(); n != 1 {
+		t.Errorf("%d tenant files open, want at most TenantMaxOpenFiles 1", n)
+	}
+	if fds, err := os.ReadDir("/proc/self/fd"); err == nil {
+		open := 0
+		for _, fd := range fds {
+			if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && (strings.HasSuffix(target, "a.log") || strings.HasSuffix(target, "b.log")) {
+				open++
+			}
+		}
+		if open > 1 {
+			t.Errorf("%d tenant files open according to /proc/self/fd, want at most 1", open)
+		}
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "a.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(got)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("a.log has %d entries, want 4:\n%s", len(lines), got)
+	}
+	for i, message := range []string{"first", "second", "third", "fourth"} {
+		if !strings.Contains(lines[i], `"msg":"`+message+`"`) {
+			t.Errorf("entry %d = %s, want %s", i, lines[i], message)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"math"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestingT is the subset of testing.TB used by the testing logger, *testing.T and *testing.B satisfy it
+type TestingT interface {
+	Log(args ...interface{})
+	Error(args ...interface{})
+}
+
+// NewTestingLogger creates a logger writing to the test's log, so code under test produces test
+// scoped output shown with the failures. Entries at ERROR and above are reported with t.Error and
+// fail the test, FATAL entries also stop it. Every level is enabled, TRACE and V(n) included.
+func NewTestingLogger(t TestingT) ILogger {
+	encoderConfig := zap.NewDevelopmentEncoderConfig()
+	encoderConfig.TimeKey = ""
+	encoderConfig.EncodeLevel = capitalLevelEncoder
+	encoder := zapcore.NewConsoleEncoder(encoderConfig)
+
+	core := zapcore.NewTee(
+		zapcore.NewCore(encoder, zapcore.AddSync(testingWriter{log: t.Log}), belowErrorEnabler{}),
+		zapcore.NewCore(encoder, zapcore.AddSync(testingWriter{log: t.Error}), zapcore.ErrorLevel),
+	)
+	// Fatal ends the test goroutine instead of exiting the test binary
+	return &zapLogger{sugar: zap.New(core, zap.WithFatalHook(zapcore.WriteThenGoexit)).Sugar()}
+}
+
+// belowErrorEnabler enables every level below ERROR, reporting the lowest level so zapcore.LevelOf
+// does not stop at DEBUG and drop TRACE and V(n) entries
+type belowErrorEnabler struct{}
+
+func (belowErrorEnabler) Enabled(level zapcore.Level) bool {
+	return level < zapcore.ErrorLevel
+}
+
+func (belowErrorEnabler) Level() zapcore.Level {
+	return zapcore.Level(math.MinInt8)
+}
+
+// testingWriter writes every encoded entry as one test log line
+type testingWriter struct {
+	log func(args ...interface{})
+}
+
+func (w testingWriter) Write(p []byte) (int, error) {
+	w.log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
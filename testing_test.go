@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+// recordingT records the lines logged and reported as errors by the testing logger
+type recordingT struct {
+	logs, errors []string
+}
+
+func (r *recordingT) Log(args ...interface{}) {
+	r.logs = append(r.logs, args[0].(string))
+}
+
+func (r *recordingT) Error(args ...interface{}) {
+	r.errors = append(r.errors, args[0].(string))
+}
+
+func TestTestingLoggerLogsEveryLevel(t *testing.T) {
+	rec := &recordingT{}
+	log := NewTestingLogger(rec)
+
+	LogAt(log, TraceLevel, "row scanned", "id", 1)
+	LogAt(log, VerbosityLevel(3), "packet received")
+	log.Debug("cache miss")
+	log.Error("query failed")
+
+	want := []string{"TRACE\trow scanned", "TRACE\tpacket received", "DEBUG\tcache miss"}
+	if len(rec.logs) != len(want) {
+		t.Fatalf("got logs %q, want %d lines", rec.logs, len(want))
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(rec.logs[i], prefix) {
+			t.Errorf("log %d = %q, want prefix %q", i, rec.logs[i], prefix)
+		}
+	}
+	if len(rec.errors) != 1 || !strings.HasPrefix(rec.errors[0], "ERROR\tquery failed") {
+		t.Errorf("got errors %q, want the query failure", rec.errors)
+	}
+}
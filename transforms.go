@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"strings"
+	"time"
+)
+
+// Transform rewrites a single field of an entry and returns its new key and value
+type Transform func(key string, value interface{}) (string, interface{})
+
+// transforms holds the registered transforms
+var transforms cowList[Transform]
+
+// AddTransform registers a transform applied, in order of registration, to every field of every
+// entry before the filters and hooks run, so all call sites converge on one schema. It can be
+// removed with the returned function.
+func AddTransform(transform Transform) (remove func()) {
+	return transforms.add(transform)
+}
+
+// RenameFields returns a transform renaming the fields by the old to new name mapping, e.g. userId to user_id
+func RenameFields(names map[string]string) Transform {
+	return func(key string, value interface{}) (string, interface{}) {
+		if newKey, ok := names[key]; ok {
+			return newKey, value
+		}
+		return key, value
+	}
+}
+
+// DurationsToMillis returns a transform converting time.Duration values to milliseconds
+func DurationsToMillis() Transform {
+	return func(key string, value interface{}) (string, interface{}) {
+		if d, ok := value.(time.Duration); ok {
+			return key, float64(d) / float64(time.Millisecond)
+		}
+		return key, value
+	}
+}
+
+// LowercaseValues returns a transform lowercasing the string values of the given fields, e.g. level names
+func LowercaseValues(keys ...string) Transform {
+	lowercaseKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		lowercaseKeys[key] = true
+	}
+	return func(key string, value interface{}) (string, interface{}) {
+		if s, ok := value.(string); ok && lowercaseKeys[key] {
+			return key, strings.ToLower(s)
+		}
+		return key, value
+	}
+}
+
+func hasTransforms() bool {
+	return len(transforms.load()) > 0
+}
+
+// transformFields applies the registered transforms to a copy of the key value fields
+func transformFields(fields []interface{}) []interface{} {
+	current := transforms.load()
+	if len(current) == 0 {
+		return fields
+	}
+	transformed := append([]interface{}(nil), fields...)
+	for i := 1; i < len(transformed); i += 2 {
+		key, ok := transformed[i-1].(string)
+		if !ok {
+			continue
+		}
+		value := transformed[i]
+		for _, transform := range current {
+			key, value = transform(key, value)
+		}
+		transformed[i-1], transformed[i] = key, value
+	}
+	return transformed
+}
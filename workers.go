@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"fmt"
+)
+
+// TemporalLogger adapts an ILogger to Temporal's log.Logger interface, so worker lifecycle and
+// activity failure logs are written with component=temporal
+type TemporalLogger struct {
+	logger ILogger
+}
+
+// NewTemporalLogger creates a Temporal logger writing through the logger, nil uses the global
+// logger. Assign it to client.Options.Logger.
+func NewTemporalLogger(logger ILogger) *TemporalLogger {
+	return &TemporalLogger{logger: logger}
+}
+
+func (t *TemporalLogger) current() ILogger {
+	if t.logger != nil {
+		return t.logger
+	}
+	return L()
+}
+
+// Debug implements Temporal's log.Logger
+func (t *TemporalLogger) Debug(msg string, keyvals ...interface{}) {
+	t.current().Debug(msg, append([]interface{}{ComponentKey, "temporal"}, keyvals...)...)
+}
+
+// Info implements Temporal's log.Logger
+func (t *TemporalLogger) Info(msg string, keyvals ...interface{}) {
+	t.current().Info(msg, append([]interface{}{ComponentKey, "temporal"}, keyvals...)...)
+}
+
+// Warn implements Temporal's log.Logger
+func (t *TemporalLogger) Warn(msg string, keyvals ...interface{}) {
+	t.current().Warn(msg, append([]interface{}{ComponentKey, "temporal"}, keyvals...)...)
+}
+
+// Error implements Temporal's log.Logger
+func (t *TemporalLogger) Error(msg string, keyvals ...interface{}) {
+	t.current().Error(msg, append([]interface{}{ComponentKey, "temporal"}, keyvals...)...)
+}
+
+// AsynqLogger adapts an ILogger to hibiken/asynq's Logger interface, so server lifecycle and task
+// failure logs are written with component=asynq
+type AsynqLogger struct {
+	logger ILogger
+}
+
+// NewAsynqLogger creates an asynq logger writing through the logger, nil uses the global logger.
+// Assign it to asynq.Config.Logger.
+func NewAsynqLogger(logger ILogger) *AsynqLogger {
+	return &AsynqLogger{logger: logger}
+}
+
+func (a *AsynqLogger) current() ILogger {
+	if a.logger != nil {
+		return a.logger
+	}
+	return L()
+}
+
+// Debug implements asynq's Logger
+func (a *AsynqLogger) Debug(args ...interface{}) {
+	a.current().Debug(fmt.Sprint(args...), ComponentKey, "asynq")
+}
+
+// Info implements asynq's Logger
+func (a *AsynqLogger) Info(args ...interface{}) {
+	a.current().Info(fmt.Sprint(args...), ComponentKey, "asynq")
+}
+
+// Warn implements asynq's Logger
+func (a *AsynqLogger) Warn(args ...interface{}) {
+	a.current().Warn(fmt.Sprint(args...), ComponentKey, "asynq")
+}
+
+// Error implements asynq's Logger
+func (a *AsynqLogger) Error(args ...interface{}) {
+	a.current().Error(fmt.Sprint(args...), ComponentKey, "asynq")
+}
+
+// Fatal implements asynq's Logger, it exits the process like asynq expects
+func (a *AsynqLogger) Fatal(args ...interface{}) {
+	a.current().Fatal(fmt.Sprint(args...), ComponentKey, "asynq")
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewLoggerWithWriter creates a standalone logger encoding entries like InitWithConfig would with
+// the config, but writing them to w instead of the configured sinks, without sampling and without
+// stack traces. The global logger is left untouched. The zap options apply last, e.g.
+// zap.WithClock for fixed times.
+func NewLoggerWithWriter(config *LoggerConfig, w io.Writer, opts ...zap.Option) (ILogger, error) {
+	if config == nil {
+		config = NewDefaultLoggerConfig()
+	}
+	if config.LogMode != "" && !isLogMode(config.LogMode) {
+		return nil, fmt.Errorf("invalid LogMode %q: must be one of TRACE, DEBUG, INFO, WARN, ERROR, FATAL", config.LogMode)
+	}
+	loggerConfig := getZapLoggerConfig(config)
+	encoder := newEncoder(config, loggerConfig.EncoderConfig, config.JsonEncoderDisabled)
+	core := zapcore.NewCore(encoder, zapcore.AddSync(w), loggerConfig.Level)
+
+	options := append(callerOptions(config), zap.AddCallerSkip(2), zap.Fields(baseFields(config)...))
+	options = append(options, config.ZapOptions...)
+	options = append(options, opts...)
+	return &zapLogger{sugar: zap.New(core, options...).Sugar()}, nil
+}
+
+// NewAuditLoggerWithWriter creates a standalone audit logger encoding entries like Audit would
+// with the config, but writing them to w. The zap options apply last.
+func NewAuditLoggerWithWriter(config *LoggerConfig, w io.Writer, opts ...zap.Option) IAuditLogger {
+	return newAuditLogger(getZapLoggerConfig(config).EncoderConfig, zapcore.AddSync(w), config, opts...)
+}
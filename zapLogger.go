@@ -8,9 +8,13 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/piyushkumar96/generic-logger/internal/logformat"
+	"github.com/piyushkumar96/generic-logger/internal/tty"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -21,11 +25,45 @@ var (
 )
 
 type zapLogger struct {
-	sugar *zap.SugaredLogger
+	sugar  *zap.SugaredLogger
+	fields []interface{} // key value pairs added to every entry, visible to the transforms, filters and hooks
+	build  *zapBuild     // what the logger was built from, nil for the no-op logger
+}
+
+// zapBuild holds what the current zap logger was built from, to derive loggers with other sinks
+type zapBuild struct {
+	config        *LoggerConfig
+	encoder       zapcore.Encoder
+	encoderConfig zapcore.EncoderConfig // of the encoder, for the JSON encoded recent entries
+	writeSyncer   zapcore.WriteSyncer
+	level         zap.AtomicLevel
+	options       []zap.Option
+	tenants       *tenantCache // sinks writing to the tenants' own files, created on first use
+	tenantsOnce   sync.Once
+	debugLogger   *zap.Logger
+	debugOnce     sync.Once
+}
+
+// sampledCore creates a core like the one of the logger, writing to the write syncer at the level
+func (b *zapBuild) sampledCore(writeSyncer zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
+	return newSampledCore(withRecentCore(newIOCore(b.encoder, writeSyncer, level), b.encoderConfig, level))
 }
 
 func initializeLoggerWithZapLogger(config *LoggerConfig) {
 	loggerConfig := getZapLoggerConfig(config)
+	if config != nil {
+		SetSampling(config.SamplingEnabled, config.SamplingInitial, config.SamplingThereafter)
+	}
+
+	var recentEntriesSize int
+
+	// Check if config is provided and use it, otherwise fallback to OS environment variable
+	if config != nil {
+		recentEntriesSize = config.RecentEntriesSize
+	} else {
+		recentEntriesSize, _ = strconv.Atoi(os.Getenv("LOGGER_RECENT_ENTRIES_SIZE"))
+	}
+	recentEntries.resize(recentEntriesSize)
 
 	var encoder zapcore.Encoder
 	var isJSONEncDisabled bool
@@ -38,14 +76,27 @@ func initializeLoggerWithZapLogger(config *LoggerConfig) {
 		isJSONEncDisabled, _ = strconv.ParseBool(isJSONEncDisabledStr)
 	}
 
-	if !isJSONEncDisabled {
-		// Create a JSON encoder for file logging
-		encoder = zapcore.NewJSONEncoder(loggerConfig.EncoderConfig)
+	var isTTYAutoDetectEnabled bool
+
+	// Check if config is provided and use it, otherwise fallback to OS environment variable
+	if config != nil {
+		isTTYAutoDetectEnabled = config.TTYAutoDetectEnabled
 	} else {
-		encoder = zapcore.NewConsoleEncoder(loggerConfig.EncoderConfig)
+		isTTYAutoDetectEnabledStr := os.Getenv("LOGGER_TTY_AUTO_DETECT_ENABLED")
+		isTTYAutoDetectEnabled, _ = strconv.ParseBool(isTTYAutoDetectEnabledStr)
 	}
 
+	// In auto detect mode a terminal gets human readable logs and no log files
+	var isInteractive bool
+	if isTTYAutoDetectEnabled {
+		isInteractive = tty.IsTerminal(os.Stdout)
+		isJSONEncDisabled = isInteractive
+	}
+
+	encoder = newEncoder(config, loggerConfig.EncoderConfig, isJSONEncDisabled)
+
 	writerSyncers := make([]zapcore.WriteSyncer, 0)
+	sinks := make([]string, 0)
 
 	var isConsoleSyncerDisabled bool
 
@@ -58,7 +109,8 @@ func initializeLoggerWithZapLogger(config *LoggerConfig) {
 	}
 	if !isConsoleSyncerDisabled {
 		// Create a zapcore.WriteSyncer for console logging
-		writerSyncers = append(writerSyncers, zapcore.AddSync(os.Stdout))
+		writerSyncers = append(writerSyncers, newCountingWriteSyncer(sinkConsole, zapcore.AddSync(os.Stdout)))
+		sinks = append(sinks, sinkConsole)
 	}
 
 	var isFileSyncerDisabled bool
@@ -70,7 +122,7 @@ func initializeLoggerWithZapLogger(config *LoggerConfig) {
 		isFileSyncerDisabledStr := os.Getenv("LOGGER_FILE_SYNCER_DISABLED")
 		isFileSyncerDisabled, _ = strconv.ParseBool(isFileSyncerDisabledStr)
 	}
-	if !isFileSyncerDisabled {
+	if !isFileSyncerDisabled && !isInteractive {
 		// Create a lumberjack logger for log file rolling
 		var logFileName string
 		var logFilePath string
@@ -117,16 +169,33 @@ func initializeLoggerWithZapLogger(config *LoggerConfig) {
 			Compress:   logFileCompress, // Whether to compress the old log files
 			LocalTime:  true,            // Use the local time zone for log rotation
 		}
-		writerSyncers = append(writerSyncers, zapcore.AddSync(lumberjackLogger))
+		writerSyncers = append(writerSyncers, newCountingWriteSyncer(sinkFile, zapcore.AddSync(lumberjackLogger)))
+		sinks = append(sinks, sinkFile)
+		registerCloser(lumberjackLogger.Close)
+
+		var isFileSyncerReopenOnHUP bool
+		if config != nil {
+			isFileSyncerReopenOnHUP = config.FileSyncerReopenOnHUP
+		} else {
+			isFileSyncerReopenOnHUPStr := os.Getenv("LOGGER_FILE_SYNCER_REOPEN_ON_HUP")
+			isFileSyncerReopenOnHUP, _ = strconv.ParseBool(isFileSyncerReopenOnHUPStr)
+		}
+		if isFileSyncerReopenOnHUP {
+			registerCloser(reopenOnSIGHUP(lumberjackLogger))
+		}
 	}
 
 	// Create a zapcore.WriteSyncer for both console and file logging
 	writeSyncer := zapcore.NewMultiWriteSyncer(writerSyncers...)
 
 	// Create a zapcore.Core with the encoders and write syncer
-	core := zapcore.NewCore(encoder, writeSyncer, loggerConfig.Level)
+	core := newSampledCore(withRecentCore(newIOCore(encoder, writeSyncer, loggerConfig.Level), loggerConfig.EncoderConfig, loggerConfig.Level))
 	// Create a new logger with the core
-	zapLog := zap.New(core, zap.AddCallerSkip(1))
+	options := append(callerOptions(config), zap.AddCallerSkip(2), zap.Hooks(countEntry), zap.Fields(baseFields(config)...))
+	if config != nil {
+		options = append(options, config.ZapOptions...)
+	}
+	zapLog := zap.New(core, options...)
 
 	defer func(zapLogger *zap.Logger) {
 		err := zapLogger.Sync()
@@ -135,7 +204,9 @@ func initializeLoggerWithZapLogger(config *LoggerConfig) {
 		}
 	}(zapLog)
 
-	Logger = &zapLogger{sugar: zapLog.Sugar()}
+	build := &zapBuild{config: config, encoder: encoder, encoderConfig: loggerConfig.EncoderConfig, writeSyncer: writeSyncer, level: loggerConfig.Level, options: options}
+	swapLogger(&zapLogger{sugar: zapLog.Sugar(), build: build})
+	auditLogger.Store(newZapAuditLogger(config, loggerConfig))
 
 	var isSocketLoggingEnabled bool
 
@@ -147,44 +218,145 @@ func initializeLoggerWithZapLogger(config *LoggerConfig) {
 		isSocketLoggingEnabled, _ = strconv.ParseBool(isSocketLoggingEnabledStr)
 	}
 
+	if isSocketLoggingEnabled {
+		sinks = append(sinks, sinkSocket)
+	}
+	setConfiguredSinks(append(sinks, sinkAudit)...)
+
 	if isSocketLoggingEnabled {
 		reInitializeLogger(config)
 	}
 }
 
+// WithOptions returns a variant of the logger with the zap options applied, e.g. a different
+// caller skip, hooks or fields, without re-running the init
+func WithOptions(opts ...zap.Option) ILogger {
+	z := currentLogger()
+	if z == nil {
+		return nopLogger
+	}
+	return &zapLogger{sugar: z.sugar.WithOptions(opts...), fields: z.fields, build: z.build}
+}
+
+// Unwrap returns the *zap.Logger of the global logger for libraries requiring one, e.g. grpc-zap
+// or otelzap, nil until the logger is initialized. Its entries reach the same sinks but skip the
+// transforms, filters and hooks.
+func Unwrap() *zap.Logger {
+	z := currentLogger()
+	if z == nil {
+		return nil
+	}
+	// The caller skip of the logger accounts for its level methods, which are not in the call stack
+	return z.sugar.Desugar().WithOptions(zap.AddCallerSkip(-2))
+}
+
 func (z *zapLogger) Write(p []byte) (n int, err error) {
 	z.Debug(string(p))
 	return len(p), nil
 }
 
 func (z *zapLogger) Debug(message string, fields ...interface{}) {
-	preprocessLog(fields)
-	z.sugar.Debugw(message, fields...)
+	z.log(zapcore.DebugLevel, message, fields)
 }
 
 func (z *zapLogger) Infof(message string, fields ...interface{}) {
 	preprocessLog(fields)
-	z.sugar.Infof(message, fields...)
+	z.log(zapcore.InfoLevel, fmt.Sprintf(message, fields...), nil)
 }
 
 func (z *zapLogger) Info(message string, fields ...interface{}) {
-	preprocessLog(fields)
-	z.sugar.Infow(message, fields...)
+	z.log(zapcore.InfoLevel, message, fields)
 }
 
 func (z *zapLogger) Warn(message string, fields ...interface{}) {
-	preprocessLog(fields)
-	z.sugar.Warnw(message, fields...)
+	z.log(zapcore.WarnLevel, message, fields)
 }
 
 func (z *zapLogger) Error(message string, fields ...interface{}) {
-	preprocessLog(fields)
-	z.sugar.Errorw(message, fields...)
+	z.log(zapcore.ErrorLevel, message, fields)
 }
 
 func (z *zapLogger) Fatal(message string, fields ...interface{}) {
+	z.log(zapcore.FatalLevel, message, fields)
+}
+
+// log runs the entry through the transforms, filters and hooks and writes it at its resulting level
+func (z *zapLogger) log(level zapcore.Level, message string, fields []interface{}) {
+	if len(z.fields) > 0 {
+		fields = append(append(make([]interface{}, 0, len(z.fields)+len(fields)), z.fields...), fields...)
+	}
 	preprocessLog(fields)
-	z.sugar.Fatalw(message, fields...)
+	if hasTransforms() || hasFilters() || hasHooks() {
+		if level < z.sugar.Level() {
+			return
+		}
+		entry := Entry{Level: level, Message: message, Fields: transformFields(fields)}
+		if isFiltered(entry) {
+			reportDropped(zapcore.Entry{Level: level, Time: time.Now(), Message: message}, DropReasonFiltered)
+			return
+		}
+		entry = runHooks(entry)
+		level, message, fields = entry.Level, entry.Message, entry.Fields
+	}
+	if level < zapcore.DebugLevel {
+		z.logVerbose(level, message, fields)
+		return
+	}
+	switch level {
+	case zapcore.DebugLevel:
+		z.sugar.Debugw(message, fields...)
+	case zapcore.InfoLevel:
+		z.sugar.Infow(message, fields...)
+	case zapcore.WarnLevel:
+		z.sugar.Warnw(message, fields...)
+	case zapcore.ErrorLevel:
+		z.sugar.Errorw(message, fields...)
+	case zapcore.DPanicLevel:
+		z.sugar.DPanicw(message, fields...)
+	case zapcore.PanicLevel:
+		z.sugar.Panicw(message, fields...)
+	default:
+		z.sugar.Fatalw(message, fields...)
+	}
+}
+
+// leveledLogger is implemented by the loggers of this package, which log at any level
+type leveledLogger interface {
+	log(level zapcore.Level, message string, fields []interface{})
+	enabled(level zapcore.Level) bool
+}
+
+// LogAt logs the message with the key value fields at the level, for adapters mapping the levels
+// of other libraries. Loggers of other packages log through the method of the level, below DEBUG
+// at DEBUG and above ERROR at ERROR.
+func LogAt(l ILogger, level zapcore.Level, message string, fields ...interface{}) {
+	if ll, ok := l.(leveledLogger); ok {
+		ll.log(level, message, fields)
+		return
+	}
+	switch {
+	case level <= zapcore.DebugLevel:
+		l.Debug(message, fields...)
+	case level == zapcore.InfoLevel:
+		l.Info(message, fields...)
+	case level == zapcore.WarnLevel:
+		l.Warn(message, fields...)
+	default:
+		l.Error(message, fields...)
+	}
+}
+
+// LevelEnabled reports whether the logger logs the entries of the level, always true for loggers
+// of other packages
+func LevelEnabled(l ILogger, level zapcore.Level) bool {
+	if ll, ok := l.(leveledLogger); ok {
+		return ll.enabled(level)
+	}
+	return true
+}
+
+func (z *zapLogger) enabled(level zapcore.Level) bool {
+	return level >= z.sugar.Level()
 }
 
 func preprocessLog(fields []interface{}) {
@@ -201,6 +373,27 @@ func preprocessLog(fields []interface{}) {
 	}
 }
 
+// newEncoder creates the JSON encoder, or the console encoder when JSON encoding is disabled
+func newEncoder(config *LoggerConfig, encoderConfig zapcore.EncoderConfig, isJSONEncDisabled bool) zapcore.Encoder {
+	if !isJSONEncDisabled {
+		// Create a JSON encoder for file logging
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+	if config != nil && config.ColorEnabled {
+		encoderConfig.EncodeLevel = capitalColorLevelEncoder
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// getHostname returns the configured hostname, the OS hostname otherwise
+func getHostname(config *LoggerConfig) string {
+	if config != nil && config.Hostname != "" {
+		return config.Hostname
+	}
+	osHostname, _ := GetHostname()
+	return osHostname
+}
+
 // GetHostname returns the hostname.
 func GetHostname() (string, error) {
 	host, err := os.Hostname()
@@ -217,7 +410,8 @@ func getZapLoggerConfig(config *LoggerConfig) zap.Config {
 	loggerConfig.Sampling = nil
 	loggerConfig.OutputPaths = []string{"stdout"}
 	loggerConfig.EncoderConfig.EncodeTime = syslogTimeEncoder
-	osHostname, _ := GetHostname()
+	loggerConfig.EncoderConfig.EncodeLevel = lowercaseLevelEncoder
+	osHostname := getHostname(config)
 	var serviceName string
 	if config != nil {
 		serviceName = config.ServiceName
@@ -225,8 +419,8 @@ func getZapLoggerConfig(config *LoggerConfig) zap.Config {
 		serviceName = os.Getenv("SERVICE")
 	}
 	initialFields := map[string]interface{}{
-		"svc":  serviceName,
-		"host": osHostname,
+		logformat.ServiceKey: serviceName,
+		logformat.HostKey:    osHostname,
 	}
 
 	loggerConfig.Level = getLoggerMode(config)
@@ -248,9 +442,12 @@ func NewSocketSyncer(config *LoggerConfig) zapcore.WriteSyncer {
 	c, err := net.Dial("tcp", net.JoinHostPort(os.Getenv("LOGGER_SOCKET_ADDRESS"), os.Getenv("LOGGER_SOCKET_PORT")))
 	if err != nil {
 		fmt.Println("failed to initialize socket logger", err.Error())
+		sinkStates[sinkSocket].failed(err)
+		reportSinkError(sinkSocket, err)
 		return nil
 	}
 
+	registerCloser(c.Close)
 	hs := &SocketSyncer{client: c, config: config}
 	ws := zapcore.Lock(hs)
 	return ws
@@ -279,12 +476,15 @@ func (w SocketSyncer) Write(p []byte) (int, error) {
 	cnt, err := w.client.Write(p)
 
 	if err != nil {
+		recordSinkError(sinkSocket, err)
 		cnt, _ = fmt.Print(string(p))
 		if errors.Is(err, syscall.EPIPE) {
 			reInitializeLogger(w.config)
 		}
-		return cnt, nil
+		return cnt, &sinkError{sink: sinkSocket, err: err, handled: true}
 	}
+	recordSinkBytes(sinkSocket, cnt)
+	recordSinkOK(sinkSocket)
 	return cnt, err
 }
 
@@ -300,6 +500,10 @@ func reInitializeLogger(config *LoggerConfig) {
 		return
 	}
 	loggerConfig := getZapLoggerConfig(config)
+	if z := currentLogger(); z != nil {
+		// Keep the level of the running logger, it may have been changed since init
+		loggerConfig.Level = z.build.level
+	}
 	jsonEncoder := zapcore.NewJSONEncoder(loggerConfig.EncoderConfig)
 	var core zapcore.Core
 	var isConsoleSyncerDisabled bool
@@ -311,14 +515,16 @@ func reInitializeLogger(config *LoggerConfig) {
 		isConsoleSyncerDisabledStr := os.Getenv("LOGGER_CONSOLE_SYNCER_DISABLED")
 		isConsoleSyncerDisabled, _ = strconv.ParseBool(isConsoleSyncerDisabledStr)
 	}
+	var writeSyncer zapcore.WriteSyncer
 	if isConsoleSyncerDisabled {
-		core = zapcore.NewCore(jsonEncoder, socketWriteSyncer, loggerConfig.Level)
+		writeSyncer = socketWriteSyncer
+		setConfiguredSinks(sinkSocket, sinkAudit)
 	} else {
-		core = zapcore.NewTee(
-			zapcore.NewCore(jsonEncoder, socketWriteSyncer, loggerConfig.Level),
-			zapcore.NewCore(jsonEncoder, sink, loggerConfig.Level),
-		)
+		writeSyncer = zapcore.NewMultiWriteSyncer(socketWriteSyncer, newCountingWriteSyncer(sinkConsole, sink))
+		setConfiguredSinks(sinkSocket, sinkConsole, sinkAudit)
 	}
+	core = newIOCore(jsonEncoder, writeSyncer, loggerConfig.Level)
+	core = newSampledCore(withRecentCore(core, loggerConfig.EncoderConfig, loggerConfig.Level))
 	zapLog := zap.New(core, opts...)
 	defer func(zapLogger *zap.Logger) {
 		err := zapLogger.Sync()
@@ -326,7 +532,8 @@ func reInitializeLogger(config *LoggerConfig) {
 			fmt.Println("Count not sync zap logger")
 		}
 	}(zapLog)
-	Logger = &zapLogger{sugar: zapLog.Sugar()}
+	build := &zapBuild{config: config, encoder: jsonEncoder, encoderConfig: loggerConfig.EncoderConfig, writeSyncer: writeSyncer, level: loggerConfig.Level, options: opts}
+	swapLogger(&zapLogger{sugar: zapLog.Sugar(), build: build})
 }
 
 func openSink() (sink, errSink zapcore.WriteSyncer, err error) {
@@ -345,8 +552,18 @@ func openSink() (sink, errSink zapcore.WriteSyncer, err error) {
 func buildOptions(config *LoggerConfig, errSink zapcore.WriteSyncer) []zap.Option {
 	stackLevel := zap.ErrorLevel
 	opts := []zap.Option{zap.ErrorOutput(errSink)}
-	opts = append(opts, zap.AddCallerSkip(1), zap.AddStacktrace(stackLevel))
-	osHostname, _ := GetHostname()
+	opts = append(opts, callerOptions(config)...)
+	opts = append(opts, zap.AddCallerSkip(2), zap.AddStacktrace(stackLevel), zap.Hooks(countEntry))
+	opts = append(opts, zap.Fields(baseFields(config)...))
+	if config != nil {
+		opts = append(opts, config.ZapOptions...)
+	}
+	return opts
+}
+
+// baseFields returns the host and service fields added to every entry, the service only when set
+func baseFields(config *LoggerConfig) []zap.Field {
+	osHostname := getHostname(config)
 
 	var serviceName string
 
@@ -356,22 +573,50 @@ func buildOptions(config *LoggerConfig, errSink zapcore.WriteSyncer) []zap.Optio
 		serviceName = os.Getenv("SERVICE")
 	}
 
-	fs := []zap.Field{zap.Any("host", osHostname), zap.Any("svc", serviceName)}
-	opts = append(opts, zap.Fields(fs...))
-	return opts
+	fields := []zap.Field{zap.Any(logformat.HostKey, osHostname)}
+	if serviceName != "" {
+		fields = append(fields, zap.Any(logformat.ServiceKey, serviceName))
+	}
+	return fields
+}
+
+// callerOptions returns the zap options to annotate logs with the caller when enabled
+func callerOptions(config *LoggerConfig) []zap.Option {
+	if config == nil || !config.CallerEnabled {
+		return nil
+	}
+	return []zap.Option{zap.AddCaller()}
 }
 
 func getLoggerMode(config *LoggerConfig) zap.AtomicLevel {
 	var loggingMode string
+	var verbosity int
 	if config != nil {
 		loggingMode = config.LogMode
+		verbosity = config.Verbosity
 	} else {
 		loggingMode = os.Getenv("LOGGER_MODE")
+		verbosity, _ = strconv.Atoi(os.Getenv("LOGGER_VERBOSITY"))
+	}
+	if verbosity > 0 {
+		return zap.NewAtomicLevelAt(VerbosityLevel(verbosity))
 	}
-	if loggingMode == "DEBUG" {
-		return zap.NewAtomicLevelAt(zap.DebugLevel)
-	} else if loggingMode == "ERROR" {
-		return zap.NewAtomicLevelAt(zap.ErrorLevel)
+	return zap.NewAtomicLevelAt(parseLogMode(loggingMode))
+}
+
+// parseLogMode returns the level of the log mode, INFO for unknown modes
+func parseLogMode(mode string) zapcore.Level {
+	switch strings.ToUpper(mode) {
+	case "TRACE":
+		return TraceLevel
+	case "DEBUG":
+		return zap.DebugLevel
+	case "WARN":
+		return zap.WarnLevel
+	case "ERROR":
+		return zap.ErrorLevel
+	case "FATAL":
+		return zap.FatalLevel
 	}
-	return zap.NewAtomicLevelAt(zap.InfoLevel)
+	return zap.InfoLevel
 }